@@ -1,16 +1,37 @@
 package app
 
 import (
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/auth"
 	"github.com/StratuStore/fsm/internal/fsm/communicator"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/handler"
+	"github.com/StratuStore/fsm/internal/fsm/health"
+	"github.com/StratuStore/fsm/internal/fsm/metrics"
+	"github.com/StratuStore/fsm/internal/fsm/ratelimit"
+	"github.com/StratuStore/fsm/internal/fsm/search"
 	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/fsm/service/access"
+	"github.com/StratuStore/fsm/internal/fsm/service/admin"
+	"github.com/StratuStore/fsm/internal/fsm/service/audit"
 	"github.com/StratuStore/fsm/internal/fsm/service/directory"
 	"github.com/StratuStore/fsm/internal/fsm/service/file"
+	"github.com/StratuStore/fsm/internal/fsm/service/recent"
+	"github.com/StratuStore/fsm/internal/fsm/service/schema"
+	"github.com/StratuStore/fsm/internal/fsm/service/shortcut"
+	"github.com/StratuStore/fsm/internal/fsm/service/smart"
+	"github.com/StratuStore/fsm/internal/fsm/service/tag"
+	"github.com/StratuStore/fsm/internal/fsm/service/tenant"
+	"github.com/StratuStore/fsm/internal/fsm/service/token"
+	"github.com/StratuStore/fsm/internal/fsm/service/workspace"
 	"github.com/StratuStore/fsm/internal/fsm/storage"
+	"github.com/StratuStore/fsm/internal/fsm/tracing"
 	"github.com/StratuStore/fsm/internal/libs/config"
 	"github.com/StratuStore/fsm/internal/libs/log"
 	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
+	"log/slog"
 )
 
 func CreateApp(cfg *config.Config) fx.Option {
@@ -22,28 +43,86 @@ func CreateApp(cfg *config.Config) fx.Option {
 			// * Common
 			newValidator,
 			log.New,
+			metrics.New,
+			fx.Annotate(tracing.New, fx.As(new(trace.TracerProvider)), fx.As(fx.Self())),
 
 			// * Storage
-			storage.New,
+			fx.Annotate(search.NewKeywordIndex, fx.As(new(search.Index))),
+			fx.Annotate(storage.New, fx.As(fx.Self()), fx.As(new(health.Storage))),
 			fx.Annotate(storage.NewDirectoryStorage, fx.As(new(directory.Storage))),
 			fx.Annotate(storage.NewFileStorage, fx.As(new(file.Storage))),
+			fx.Annotate(storage.NewSavedSearchStorage, fx.As(new(smart.Storage))),
+			fx.Annotate(storage.NewTagStorage, fx.As(new(tag.Storage))),
+			fx.Annotate(storage.NewAttrSchemaStorage, fx.As(new(schema.Storage))),
+			fx.Annotate(storage.NewShortcutStorage, fx.As(new(shortcut.Storage))),
+			fx.Annotate(storage.NewWorkspaceStorage, fx.As(new(workspace.Storage)), fx.As(new(access.Storage))),
+			fx.Annotate(storage.NewTenantStorage, fx.As(new(tenant.Storage))),
+			fx.Annotate(storage.NewActivityStorage, fx.As(new(recent.Storage)), fx.As(new(recent.Writer))),
+			fx.Annotate(storage.NewAuditStorage, fx.As(new(audit.Storage)), fx.As(new(audit.Writer))),
+			fx.Annotate(storage.NewAPITokenStorage, fx.As(new(token.Storage)), fx.As(new(auth.TokenStorage))),
+			fx.Annotate(storage.NewAdminStorage, fx.As(new(admin.Storage))),
+			newRateLimitStore,
 
 			// * Services
-			fx.Annotate(communicator.New, fx.As(new(service.Communicator)), fx.As(fx.Self())),
-			fx.Annotate(directory.New, fx.As(new(handler.DirectoryService))),
-			fx.Annotate(file.New, fx.As(new(handler.FileService))),
+			fx.Annotate(access.New, fx.As(new(service.Access))),
+			fx.Annotate(communicator.New, fx.As(new(service.Communicator)), fx.As(new(health.Communicator)), fx.As(fx.Self())),
+			fx.Annotate(health.New, fx.As(new(handler.HealthChecker))),
+			ratelimit.New,
+			auth.New,
+			fx.Annotate(recent.NewRecorder, fx.As(new(service.ActivityRecorder)), fx.As(fx.Self())),
+			fx.Annotate(audit.NewRecorder, fx.As(new(service.AuditTrail)), fx.As(fx.Self())),
+			fx.Annotate(directory.New, fx.As(new(handler.DirectoryService)), fx.As(new(handler.StarredService)), fx.As(new(smart.Searcher))),
+			fx.Annotate(file.New, fx.As(new(handler.FileService)), fx.As(fx.Self())),
+			fx.Annotate(smart.New, fx.As(new(handler.SmartService))),
+			fx.Annotate(tag.New, fx.As(new(handler.TagService))),
+			fx.Annotate(schema.New, fx.As(new(handler.SchemaService))),
+			fx.Annotate(recent.New, fx.As(new(handler.RecentService))),
+			fx.Annotate(shortcut.New, fx.As(new(handler.ShortcutService))),
+			fx.Annotate(workspace.New, fx.As(new(handler.WorkspaceService))),
+			fx.Annotate(tenant.New, fx.As(new(handler.TenantService))),
+			fx.Annotate(audit.New, fx.As(new(handler.AuditService))),
+			fx.Annotate(token.New, fx.As(new(handler.TokenService))),
+			fx.Annotate(admin.New, fx.As(new(handler.AdminService))),
 
 			// * Handlers
 			handler.NewDirectoryHandler,
 			handler.NewFileHandler,
+			handler.NewSmartHandler,
+			handler.NewTagHandler,
+			handler.NewSchemaHandler,
+			handler.NewRecentHandler,
+			handler.NewStarredHandler,
+			handler.NewShortcutHandler,
+			handler.NewWorkspaceHandler,
+			handler.NewTenantHandler,
+			handler.NewAuditHandler,
+			handler.NewTokenHandler,
+			handler.NewAdminHandler,
 			handler.New,
 		),
 		fx.Invoke(
+			// the tracer provider is stopped last, after the spans of the last requests ended
+			startTracing,
+			// the storage is stopped after the recorders, which write to it
+			startStorage,
+			// the recorders are started first, so they are stopped after the server and flush the last records
+			startRecorder,
+			startAuditRecorder,
+			startAuth,
 			startHTTPServer,
+			registerCommitHandler,
+			registerTotals,
 		),
 	)
 }
 
+func startStorage(lifecycle fx.Lifecycle, s *storage.Storage) {
+	lifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+}
+
 func startHTTPServer(lifecycle fx.Lifecycle, h *handler.Handler) {
 	lifecycle.Append(fx.Hook{
 		OnStart: h.Start,
@@ -51,6 +130,68 @@ func startHTTPServer(lifecycle fx.Lifecycle, h *handler.Handler) {
 	})
 }
 
-func newValidator() *validator.Validate {
-	return validator.New(validator.WithRequiredStructEnabled())
+func startAuth(lifecycle fx.Lifecycle, a *auth.Authenticator) {
+	lifecycle.Append(fx.Hook{
+		OnStop: a.Stop,
+	})
+}
+
+func startTracing(lifecycle fx.Lifecycle, p *tracing.Provider) {
+	lifecycle.Append(fx.Hook{
+		OnStop: p.Stop,
+	})
+}
+
+func startRecorder(lifecycle fx.Lifecycle, r *recent.Recorder) {
+	lifecycle.Append(fx.Hook{
+		OnStart: r.Start,
+		OnStop:  r.Stop,
+	})
+}
+
+func startAuditRecorder(lifecycle fx.Lifecycle, r *audit.Recorder) {
+	lifecycle.Append(fx.Hook{
+		OnStart: r.Start,
+		OnStop:  r.Stop,
+	})
+}
+
+func registerCommitHandler(comm *communicator.Communicator, fileService *file.Service) {
+	comm.OnCommit(fileService.Commit)
+}
+
+func registerTotals(l *slog.Logger, m *metrics.Metrics, s *storage.Storage) error {
+	return m.Register(metrics.NewTotalsCollector(l, s))
+}
+
+// newRateLimitStore keeps the buckets in MongoDB if the limits are shared between replicas, otherwise in memory.
+func newRateLimitStore(cfg *config.Config, s *storage.Storage) (ratelimit.Store, error) {
+	switch cfg.RateLimitStore {
+	case "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "mongodb":
+		return storage.NewRateLimitStorage(s), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
+	}
+}
+
+func newValidator() (*validator.Validate, error) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	validations := map[string]validator.Func{
+		"sortfields":       core.ValidateSortFields,
+		"searchsortfields": core.ValidateSearchSortFields,
+		"mimegroup":        core.ValidateMimeGroup,
+		"attrpredicate":    core.ValidateAttrPredicate,
+		"attrkey":          core.ValidateAttrKey,
+		"mimepattern":      core.ValidateMimePattern,
+	}
+	for tag, f := range validations {
+		if err := v.RegisterValidation(tag, f); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
 }
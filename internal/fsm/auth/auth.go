@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/utils"
+	jwtware "github.com/gofiber/contrib/jwt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	ScopeClaim = "scope"
+	// ScpClaim is the list of scopes some identity providers issue instead of ScopeClaim
+	ScpClaim = "scp"
+)
+
+var ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+
+type keySet interface {
+	Keyfunc(token *jwt.Token) (any, error)
+}
+
+// Authenticator verifies the JWTs and API tokens of requests and stores them in the locals, see handler.GetIdentity.
+type Authenticator struct {
+	l             *slog.Logger
+	tokens        TokenStorage
+	secret        []byte
+	keys          keySet
+	validator     *jwt.Validator
+	requireScopes bool
+	stop          func()
+}
+
+func New(l *slog.Logger, cfg *config.Config, tokens TokenStorage) (*Authenticator, error) {
+	a := &Authenticator{
+		l:             l.With(slog.String("module", "internal.fsm.auth.Authenticator")),
+		tokens:        tokens,
+		secret:        []byte(cfg.JWTSecret),
+		requireScopes: cfg.AuthRequireScopes,
+		stop:          func() {},
+	}
+
+	var opts []jwt.ParserOption
+	if cfg.AuthIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.AuthIssuer))
+	}
+	if cfg.AuthAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.AuthAudience))
+	}
+	a.validator = jwt.NewValidator(opts...)
+
+	switch {
+	case cfg.AuthJWKSURL != "" && cfg.AuthJWKSFile != "":
+		return nil, errors.New("only one of AUTH_JWKS_URL and AUTH_JWKS_FILE can be set")
+	case cfg.AuthJWKSURL != "":
+		jwks, err := keyfunc.Get(cfg.AuthJWKSURL, keyfunc.Options{
+			RefreshErrorHandler: func(err error) {
+				a.l.Error("unable to refresh JWKS", slog.String("err", err.Error()))
+			},
+			RefreshInterval:   cfg.AuthJWKSRefresh,
+			RefreshRateLimit:  time.Minute,
+			RefreshTimeout:    10 * time.Second,
+			RefreshUnknownKID: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get JWKS: %w", err)
+		}
+		a.keys = jwks
+		a.stop = jwks.EndBackground
+	case cfg.AuthJWKSFile != "":
+		keys := newFileKeys(a.l, cfg.AuthJWKSFile)
+		if _, err := keys.current(); err != nil {
+			return nil, err
+		}
+		a.keys = keys
+	}
+
+	if len(a.secret) == 0 && a.keys == nil {
+		return nil, errors.New("neither AUTH_SECRET nor a JWKS is configured")
+	}
+
+	return a, nil
+}
+
+// Middleware rejects requests without a valid JWT or API token.
+func (a *Authenticator) Middleware() fiber.Handler {
+	verifyJWT := jwtware.New(jwtware.Config{
+		KeyFunc:        a.keyfunc,
+		SuccessHandler: a.authorize,
+	})
+
+	return func(c *fiber.Ctx) error {
+		if secret, ok := apiTokenSecret(c); ok && a.tokens != nil {
+			return a.authenticateAPIToken(c, secret)
+		}
+
+		return verifyJWT(c)
+	}
+}
+
+func (a *Authenticator) Stop(_ context.Context) error {
+	a.stop()
+
+	return nil
+}
+
+// keyfunc picks the key by the algorithm of the token, the key types of the algorithms differ,
+// so a token can not be verified with a key meant for another algorithm.
+func (a *Authenticator) keyfunc(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS512.Alg():
+		if len(a.secret) == 0 {
+			return nil, ErrUnsupportedAlgorithm
+		}
+
+		return a.secret, nil
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg():
+		if a.keys == nil {
+			return nil, ErrUnsupportedAlgorithm
+		}
+
+		return a.keys.Keyfunc(token)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// authorize checks the issuer and audience of a verified token and grants its scopes.
+func (a *Authenticator) authorize(c *fiber.Ctx) error {
+	token := c.Locals("user").(*jwt.Token)
+
+	if err := a.validator.Validate(token.Claims); err != nil {
+		a.l.Debug("invalid claims", slog.String("request_id", handler.GetRequestID(c)), slog.String("err", err.Error()))
+
+		return c.Status(http.StatusUnauthorized).JSON(utils.NewErrorResponse("authentification error"))
+	}
+
+	scopes, ok := Scopes(token.Claims.(jwt.MapClaims))
+	if !ok {
+		if a.requireScopes {
+			return c.Status(http.StatusForbidden).JSON(utils.NewErrorResponse("token has no scopes"))
+		}
+		scopes = handler.AllScopes
+	}
+	c.Locals(handler.ScopesLocal, scopes)
+
+	return c.Next()
+}
+
+// Scopes reads the space separated ScopeClaim or the ScpClaim list, ok is false if the token has neither.
+func Scopes(claims jwt.MapClaims) (scopes []string, ok bool) {
+	if scope, ok := claims[ScopeClaim].(string); ok {
+		return strings.Fields(scope), true
+	}
+
+	switch scp := claims[ScpClaim].(type) {
+	case string:
+		return strings.Fields(scp), true
+	case []any:
+		for _, scope := range scp {
+			if s, ok := scope.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+
+		return scopes, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func writeJWKS(t *testing.T, key *ecdsa.PrivateKey, kid string) string {
+	jwk := map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"kid": kid,
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+	data, err := json.Marshal(map[string]any{"keys": []any{jwk}})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func TestMiddleware(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	a, err := New(slog.Default(), &config.Config{
+		Handler: config.Handler{JWTSecret: "secret"},
+		Auth:    config.Auth{AuthJWKSFile: writeJWKS(t, key, "k1"), AuthIssuer: "https://idp"},
+	}, nil)
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(a.Middleware())
+	app.Get("/", handler.RequireScope(handler.ScopeFilesWrite), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	status := func(token string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+	es256 := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		return signed
+	}
+	hs := func(method jwt.SigningMethod, claims jwt.MapClaims) string {
+		signed, err := jwt.NewWithClaims(method, claims).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		return signed
+	}
+
+	require.Equal(t, fiber.StatusOK, status(es256(jwt.MapClaims{"sub": "u", "iss": "https://idp", "scope": "files:read files:write"})))
+	require.Equal(t, fiber.StatusForbidden, status(es256(jwt.MapClaims{"sub": "u", "iss": "https://idp", "scp": []string{"files:read"}})))
+	require.Equal(t, fiber.StatusUnauthorized, status(es256(jwt.MapClaims{"sub": "u", "iss": "https://other"})))
+	// tokens without scope claims are granted every scope
+	require.Equal(t, fiber.StatusOK, status(hs(jwt.SigningMethodHS512, jwt.MapClaims{"id": "u", "iss": "https://idp"})))
+	require.Equal(t, fiber.StatusUnauthorized, status(hs(jwt.SigningMethodHS256, jwt.MapClaims{"id": "u", "iss": "https://idp"})))
+}
+
+func TestScopes(t *testing.T) {
+	scopes, ok := Scopes(jwt.MapClaims{"scope": " files:read  files:write"})
+	require.True(t, ok)
+	require.Equal(t, []string{"files:read", "files:write"}, scopes)
+
+	scopes, ok = Scopes(jwt.MapClaims{"scp": []any{"files:read"}})
+	require.True(t, ok)
+	require.Equal(t, []string{"files:read"}, scopes)
+
+	_, ok = Scopes(jwt.MapClaims{"id": "u"})
+	require.False(t, ok)
+}
+
+type tokens struct {
+	tokens  map[string]*core.APIToken
+	touched []types.ObjectId
+}
+
+func (s *tokens) GetByHash(_ context.Context, hash string) (*core.APIToken, error) {
+	if token, ok := s.tokens[hash]; ok {
+		return token, nil
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+func (s *tokens) Touch(_ context.Context, id types.ObjectId, _ time.Time) error {
+	s.touched = append(s.touched, id)
+
+	return nil
+}
+
+func TestAPIToken(t *testing.T) {
+	recently := time.Now().Add(-time.Second)
+	store := &tokens{tokens: map[string]*core.APIToken{
+		core.HashAPIToken("fsm_valid"): {
+			ID: "t1", UserID: "u", TenantID: "acme", Scopes: []string{handler.ScopeFilesRead},
+			DirectoryID: "65f000000000000000000001", ExpiresAt: time.Now().Add(time.Hour),
+		},
+		core.HashAPIToken("fsm_used"):    {ID: "t2", UserID: "u", ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: &recently},
+		core.HashAPIToken("fsm_expired"): {ID: "t3", UserID: "u", ExpiresAt: time.Now().Add(-time.Second)},
+	}}
+	a, err := New(slog.Default(), &config.Config{Handler: config.Handler{JWTSecret: "secret"}}, store)
+	require.NoError(t, err)
+
+	var identity owncontext.Identity
+	var scopes []string
+	app := fiber.New()
+	app.Use(a.Middleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		identity, err = handler.GetIdentity(slog.Default(), c)
+		require.NoError(t, err)
+		scopes = c.Locals(handler.ScopesLocal).([]string)
+
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Use(handler.DenyAPITokens())
+	app.Get("/tag", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	status := func(path, secret string) int {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+secret)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+
+	require.Equal(t, fiber.StatusOK, status("/", "fsm_valid"))
+	require.Equal(t, "u", identity.UserID)
+	require.Equal(t, "acme", identity.TenantID)
+	require.Equal(t, "65f000000000000000000001", identity.Subtree)
+	require.Equal(t, []string{handler.ScopeFilesRead}, scopes)
+
+	require.Equal(t, fiber.StatusOK, status("/", "fsm_used"))
+	require.Equal(t, []types.ObjectId{"t1"}, store.touched, "recent uses are not written again")
+
+	require.Equal(t, fiber.StatusUnauthorized, status("/", "fsm_expired"))
+	require.Equal(t, fiber.StatusUnauthorized, status("/", "fsm_unknown"))
+	require.Equal(t, fiber.StatusForbidden, status("/tag", "fsm_valid"))
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"fmt"
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileCheckInterval is how often fileKeys looks for a changed file.
+const fileCheckInterval = 10 * time.Second
+
+// fileKeys is a JWKS read from a file, it is reloaded when the file changes.
+// If the new file can not be read, the previous keys are kept.
+type fileKeys struct {
+	l       *slog.Logger
+	path    string
+	mu      sync.Mutex
+	jwks    *keyfunc.JWKS
+	modTime time.Time
+	checked time.Time
+}
+
+func newFileKeys(l *slog.Logger, path string) *fileKeys {
+	return &fileKeys{
+		l:    l,
+		path: path,
+	}
+}
+
+func (k *fileKeys) Keyfunc(token *jwt.Token) (any, error) {
+	jwks, err := k.current()
+	if err != nil {
+		return nil, err
+	}
+
+	return jwks.Keyfunc(token)
+}
+
+func (k *fileKeys) current() (*keyfunc.JWKS, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.jwks != nil && time.Since(k.checked) < fileCheckInterval {
+		return k.jwks, nil
+	}
+	k.checked = time.Now()
+
+	jwks, err := k.load()
+	if err != nil {
+		if k.jwks == nil {
+			return nil, err
+		}
+		k.l.Error("unable to reload JWKS, keeping the previous keys", slog.String("err", err.Error()))
+
+		return k.jwks, nil
+	}
+	if jwks != nil {
+		k.jwks = jwks
+	}
+
+	return k.jwks, nil
+}
+
+// load reads the file if it changed since the last load, otherwise it returns nil.
+func (k *fileKeys) load() (*keyfunc.JWKS, error) {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat JWKS file: %w", err)
+	}
+	if k.jwks != nil && info.ModTime().Equal(k.modTime) {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read JWKS file: %w", err)
+	}
+	jwks, err := keyfunc.NewJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse JWKS file: %w", err)
+	}
+	k.modTime = info.ModTime()
+
+	return jwks, nil
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// touchInterval limits how often the last use of a token is written.
+const touchInterval = time.Minute
+
+type TokenStorage interface {
+	GetByHash(ctx context.Context, hash string) (*core.APIToken, error)
+	Touch(ctx context.Context, id types.ObjectId, at time.Time) error
+}
+
+// apiTokenSecret returns the bearer token of the request if it is an API token rather than a JWT.
+func apiTokenSecret(c *fiber.Ctx) (string, bool) {
+	secret, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+
+	return secret, ok && strings.HasPrefix(secret, core.APITokenPrefix)
+}
+
+func (a *Authenticator) authenticateAPIToken(c *fiber.Ctx, secret string) error {
+	l := a.l.With(slog.String("op", "authenticateAPIToken"), slog.String("request_id", handler.GetRequestID(c)))
+
+	now := time.Now()
+	token, err := a.tokens.GetByHash(c.UserContext(), core.HashAPIToken(secret))
+	if errors.Is(err, mongo.ErrNoDocuments) || err == nil && !now.Before(token.ExpiresAt) {
+		return c.Status(http.StatusUnauthorized).JSON(utils.NewErrorResponse("authentification error"))
+	}
+	if err != nil {
+		l.Error("unable to get api token", slog.String("err", err.Error()))
+
+		return c.Status(http.StatusInternalServerError).JSON(utils.NewErrorResponse("internal error"))
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= touchInterval {
+		if err := a.tokens.Touch(c.UserContext(), token.ID, now); err != nil {
+			l.Error("unable to update last use of api token", slog.String("err", err.Error()))
+		}
+	}
+
+	// the token is stored as the claims of a JWT, so handler.GetIdentity reads both alike
+	claims := jwt.MapClaims{"id": token.UserID, handler.TenantClaim: token.TenantID}
+	if !token.DirectoryID.IsZero() {
+		claims[handler.SubtreeClaim] = string(token.DirectoryID)
+	}
+	c.Locals("user", &jwt.Token{Claims: claims, Valid: true})
+	c.Locals(handler.ScopesLocal, token.Scopes)
+	c.Locals(handler.APITokenLocal, string(token.ID))
+
+	return c.Next()
+}
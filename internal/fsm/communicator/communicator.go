@@ -4,14 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/metrics"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/fsm/tracing"
 	"github.com/StratuStore/fsm/internal/libs/config"
 	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/StratuStore/fsm/internal/libs/ownerrors"
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-amqp/v3/pkg/amqp"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/mbretter/go-mongodb/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"log/slog"
 	"net/http"
 	"sync"
@@ -19,6 +29,9 @@ import (
 
 const serviceAccountID = "fs"
 
+// CommitHandler is called when the FS reports a committed upload of the file.
+type CommitHandler func(ctx context.Context, fileID types.ObjectId, hash, mimeType string) error
+
 type Communicator struct {
 	l     *slog.Logger
 	pub   *amqp.Publisher
@@ -26,9 +39,20 @@ type Communicator struct {
 	host  string
 	m     sync.Map
 	g     GobMarshaler
+	t     service.AuditTrail
+	mt    *metrics.Metrics
+	tr    trace.Tracer
+	// onCommit is set once during startup by OnCommit
+	onCommit CommitHandler
 }
 
-func New(l *slog.Logger, cfg *config.Config) (*Communicator, error) {
+func New(
+	l *slog.Logger,
+	cfg *config.Config,
+	t service.AuditTrail,
+	mt *metrics.Metrics,
+	tp trace.TracerProvider,
+) (*Communicator, error) {
 	publisher, err := amqp.NewPublisher(
 		amqp.NewNonDurablePubSubConfig(cfg.RabbitMQ.URN, nil),
 		watermill.NewSlogLogger(l.With(slog.String("module", "watermill-ampq"))),
@@ -42,6 +66,9 @@ func New(l *slog.Logger, cfg *config.Config) (*Communicator, error) {
 		pub:   publisher,
 		topic: cfg.Topic,
 		host:  cfg.RabbitMQ.Host,
+		t:     t,
+		mt:    mt,
+		tr:    tracing.Tracer(tp),
 	}, nil
 }
 
@@ -59,16 +86,61 @@ func (c *Communicator) Handler(ctx *fiber.Ctx) error {
 		return ownerrors.NewValidationError(l, "unable to parse request body with gob", "wrong data format", err)
 	}
 
+	// callbacks are made on behalf of the FS, which works for every tenant
+	userCtx := owncontext.Unscoped(owncontext.NewWithIdentity(ctx.UserContext(), owncontext.Identity{
+		UserID:    serviceAccountID,
+		ClientIP:  ctx.IP(),
+		RequestID: handler.GetRequestID(ctx),
+	}))
+
+	if r.Type == CommitType {
+		return c.commit(userCtx, ctx, &r)
+	}
+
 	process, ok := c.m.Load(r.ID)
 	if !ok {
+		c.t.Record(userCtx, core.AuditFSCallbackLost, nil, nil, callbackValues(&r))
 		return ctx.SendStatus(http.StatusResetContent)
 	}
 	p := process.(*Process)
 
 	if ok := p.Set(&r); !ok {
+		c.t.Record(userCtx, core.AuditFSCallbackLost, nil, nil, callbackValues(&r))
+		return ctx.SendStatus(http.StatusResetContent)
+	}
+	c.t.Record(userCtx, core.AuditFSCallback, nil, nil, callbackValues(&r))
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// OnCommit registers the handler of CommitType notifications.
+func (c *Communicator) OnCommit(handler CommitHandler) {
+	c.onCommit = handler
+}
+
+// callbackValues are the audited fields of a callback, see core.AuditFSCallback.
+func callbackValues(r *Response) core.AuditValues {
+	return core.AuditValues{
+		"requestID": r.ID.String(),
+		"type":      r.Type.String(),
+		"host":      r.Host,
+		"err":       r.Err,
+	}
+}
+
+func (c *Communicator) commit(userCtx context.Context, ctx *fiber.Ctx, r *Response) error {
+	l := c.l.With(slog.String("op", "commit"))
+
+	if c.onCommit == nil {
+		l.Warn("commit received without handler", slog.Any("fileID", r.FileID))
+
 		return ctx.SendStatus(http.StatusResetContent)
 	}
 
+	if err := c.onCommit(userCtx, FileID(r.FileID), r.Hash, r.MimeType); err != nil {
+		return err
+	}
+
 	return ctx.SendStatus(http.StatusNoContent)
 }
 
@@ -131,6 +203,37 @@ func (c *Communicator) Update(ctx context.Context, id types.ObjectId, size uint)
 	return response.ToReturn()
 }
 
+// CheckConnection returns an error if the publisher is not connected to the broker.
+func (c *Communicator) CheckConnection() error {
+	if c.pub.Closed() {
+		return errors.New("publisher is closed")
+	}
+	if !c.pub.IsConnected() {
+		return errors.New("publisher is not connected to the broker")
+	}
+
+	return nil
+}
+
+// Ping makes a round trip to an FS node.
+func (c *Communicator) Ping(ctx context.Context) error {
+	request := &Request{
+		ID:   uuid.New(),
+		Host: c.host,
+		Type: PingType,
+	}
+
+	response, err := c.makeRequest(ctx, request)
+	if err != nil {
+		return err
+	}
+	if response.Err != "" {
+		return errors.New(response.Err)
+	}
+
+	return nil
+}
+
 func (c *Communicator) makeRequest(ctx context.Context, r *Request) (*Response, error) {
 	l := c.l.With(slog.String("op", "makeRequest"))
 
@@ -140,6 +243,8 @@ func (c *Communicator) makeRequest(ctx context.Context, r *Request) (*Response,
 
 		return nil, fmt.Errorf("should not be possible: duplicate of request inside map occurred")
 	}
+	// late callbacks of given up requests are answered with StatusResetContent
+	defer c.m.Delete(r.ID)
 
 	payload, err := c.g.Marshal(r)
 	if err != nil {
@@ -147,20 +252,41 @@ func (c *Communicator) makeRequest(ctx context.Context, r *Request) (*Response,
 
 		return nil, fmt.Errorf("cannot marshal data using gob: %w", err)
 	}
-	if err := c.pub.Publish(c.topic, message.NewMessage(r.ID.String(), payload)); err != nil {
+	attrs := trace.WithAttributes(
+		attribute.String("fsm.fs.type", r.Type.String()),
+		attribute.String("fsm.fs.request_id", r.ID.String()),
+	)
+
+	// the FS continues the trace from the metadata and sends it back in the headers of its callback
+	publishCtx, span := c.tr.Start(ctx, "fs.publish "+r.Type.String(), trace.WithSpanKind(trace.SpanKindProducer), attrs)
+	msg := message.NewMessage(r.ID.String(), payload)
+	tracing.Propagator.Inject(publishCtx, propagation.MapCarrier(msg.Metadata))
+	err = c.pub.Publish(c.topic, msg)
+	endSpan(span, err)
+	if err != nil {
 		l.Error("unable to send message to queue", slog.String("err", err.Error()))
 
 		return nil, fmt.Errorf("unable to send message to queue: %w", err)
 	}
 
+	observe := c.mt.ObserveFS(r.Type.String())
+	_, span = c.tr.Start(ctx, "fs.wait "+r.Type.String(), attrs)
 	response, err := p.WaitAndGet(ctx)
+	observe(err)
+	endSpan(span, err)
 	if err != nil {
 		l.Error("cannot get response from FS", slog.String("err", err.Error()))
 
 		return nil, err
 	}
 
-	c.m.Delete(r.ID)
-
 	return response, nil
 }
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -12,7 +12,7 @@ type Process struct {
 
 func NewProcess() *Process {
 	return &Process{
-		result: make(chan *Response),
+		result: make(chan *Response, 1), // Set never blocks, even if nobody waits anymore
 	}
 }
 
@@ -15,8 +15,29 @@ const (
 	UpdateType
 	OpenType
 	DeleteType
+	// CommitType is sent by the FS without a preceding request, once an upload started by Create or Update is stored
+	CommitType
+	// PingType is answered by the FS without doing anything, it is used by the health checks
+	PingType
 )
 
+var requestTypeNames = [...]string{
+	CreateType: "create",
+	UpdateType: "update",
+	OpenType:   "open",
+	DeleteType: "delete",
+	CommitType: "commit",
+	PingType:   "ping",
+}
+
+func (t RequestType) String() string {
+	if t < 0 || int(t) >= len(requestTypeNames) {
+		return fmt.Sprintf("RequestType(%d)", int(t))
+	}
+
+	return requestTypeNames[t]
+}
+
 type Request struct {
 	ID     uuid.UUID
 	Host   string
@@ -31,6 +52,17 @@ func NewRequest(requestType RequestType, host string, fileID types.ObjectId, siz
 		return nil, fmt.Errorf("unable to convert fileID to objectID")
 	}
 
+	return &Request{
+		ID:     uuid.New(),
+		Type:   requestType,
+		Host:   host,
+		FileID: FileUUID(objID),
+		Size:   size,
+	}, nil
+}
+
+// FileUUID packs the 12 bytes of an ObjectID into a version 4 UUID, leaving the version and variant bits free.
+func FileUUID(objID primitive.ObjectID) uuid.UUID {
 	var fileUUID uuid.UUID
 	copy(fileUUID[:6], objID[:6])
 	copy(fileUUID[10:], objID[6:])
@@ -39,13 +71,16 @@ func NewRequest(requestType RequestType, host string, fileID types.ObjectId, siz
 	fileUUID[8] &= 0x3f /* clear variant        */
 	fileUUID[8] |= 0x80 /* set to IETF variant  */
 
-	return &Request{
-		ID:     uuid.New(),
-		Type:   requestType,
-		Host:   host,
-		FileID: fileUUID,
-		Size:   size,
-	}, nil
+	return fileUUID
+}
+
+// FileID is the reverse of FileUUID.
+func FileID(fileUUID uuid.UUID) types.ObjectId {
+	var objID primitive.ObjectID
+	copy(objID[:6], fileUUID[:6])
+	copy(objID[6:], fileUUID[10:])
+
+	return types.ObjectId(objID.Hex())
 }
 
 type Response struct {
@@ -53,6 +88,10 @@ type Response struct {
 	Host         string
 	ConnectionID uuid.UUID
 	Err          string
+	Type         RequestType
+	FileID       uuid.UUID // set for CommitType
+	Hash         string    // hex SHA-256 of the committed content, set for CommitType
+	MimeType     string    // sniffed from the committed content, optional for CommitType
 }
 
 func (r *Response) ToReturn() (string, string, error) {
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/mbretter/go-mongodb/types"
+	"strconv"
+	"time"
+)
+
+type AttrType string
+
+const (
+	AttrString AttrType = "string"
+	AttrNumber AttrType = "number"
+	AttrBool   AttrType = "bool"
+	AttrDate   AttrType = "date"
+)
+
+// AttrField describes a single attribute key. Rules are extra validator tags
+// applied to the value, e.g. "max=64" or "oneof=draft final".
+type AttrField struct {
+	Key      string   `json:"key" bson:"key" validate:"required,attrkey"`
+	Type     AttrType `json:"type" bson:"type" validate:"omitempty,oneof=string number bool date"`
+	Required bool     `json:"required" bson:"required"`
+	Rules    string   `json:"rules" bson:"rules" validate:"-"`
+}
+
+// AttrSchema constrains File.Attrs of the files of a user. A schema with a DirectoryID
+// applies to the files below that directory and takes precedence over the schema of the user.
+// Strict schemas reject keys that are not listed in Fields.
+type AttrSchema struct {
+	ID          types.ObjectId `json:"id" bson:"_id,omitempty"`
+	UserID      string         `json:"userID" bson:"userID"`
+	TenantID    string         `json:"-" bson:"tenantID"`
+	DirectoryID string         `json:"directoryID,omitempty" bson:"directoryID"`
+	Fields      []AttrField    `json:"fields" bson:"fields"`
+	Strict      bool           `json:"strict" bson:"strict"`
+	CreatedAt   time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// parse converts value to the Go type of the field, so rules such as "max=1000"
+// compare numbers and dates rather than string lengths.
+func (f *AttrField) parse(value string) (any, error) {
+	switch f.Type {
+	case AttrNumber:
+		return strconv.ParseFloat(value, 64)
+	case AttrBool:
+		return strconv.ParseBool(value)
+	case AttrDate:
+		return time.Parse(time.DateOnly, value)
+	default:
+		return value, nil
+	}
+}
+
+// zero returns the zero value of the Go type of the field.
+func (f *AttrField) zero() any {
+	switch f.Type {
+	case AttrNumber:
+		return float64(0)
+	case AttrBool:
+		return false
+	case AttrDate:
+		return time.Time{}
+	default:
+		return ""
+	}
+}
+
+// CheckRules reports whether v accepts the rules of every field. Unknown or malformed
+// validator tags make validator panic, so they are caught here once, when the schema is saved.
+func (s *AttrSchema) CheckRules(v *validator.Validate) (err error) {
+	var key string
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid rules of attribute %q: %v", key, r)
+		}
+	}()
+
+	for _, field := range s.Fields {
+		key = field.Key
+		if field.Rules != "" {
+			_ = v.Var(field.zero(), field.Rules)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks attrs against the schema and returns every violation joined.
+func (s *AttrSchema) Validate(v *validator.Validate, attrs map[string]string) error {
+	var errs []error
+	known := make(map[string]bool, len(s.Fields))
+	for _, field := range s.Fields {
+		known[field.Key] = true
+
+		value, ok := attrs[field.Key]
+		if !ok {
+			if field.Required {
+				errs = append(errs, fmt.Errorf("attribute %q is required", field.Key))
+			}
+			continue
+		}
+
+		typed, err := field.parse(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("attribute %q must be a %v", field.Key, field.Type))
+			continue
+		}
+		if field.Rules == "" {
+			continue
+		}
+		if err := v.Var(typed, field.Rules); err != nil {
+			errs = append(errs, fmt.Errorf("attribute %q does not satisfy %q", field.Key, field.Rules))
+		}
+	}
+
+	if s.Strict {
+		for key := range attrs {
+			if !known[key] {
+				errs = append(errs, fmt.Errorf("attribute %q is not allowed", key))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateAttrKey is a validator.Func for the "attrkey" tag.
+func ValidateAttrKey(fl validator.FieldLevel) bool {
+	return ValidAttrKey(fl.Field().String())
+}
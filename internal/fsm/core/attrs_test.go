@@ -0,0 +1,93 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func newAttrValidator(t *testing.T) *validator.Validate {
+	v := validator.New()
+	require.NoError(t, v.RegisterValidation("attrkey", ValidateAttrKey))
+
+	return v
+}
+
+func TestAttrSchemaValidate(t *testing.T) {
+	v := newAttrValidator(t)
+	schema := AttrSchema{
+		Fields: []AttrField{
+			{Key: "project", Required: true},
+			{Key: "pages", Type: AttrNumber, Rules: "max=1000"},
+			{Key: "reviewed", Type: AttrBool},
+			{Key: "due", Type: AttrDate},
+			{Key: "status", Rules: "oneof=draft final"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		strict bool
+		attrs  map[string]string
+		errs   []string
+	}{
+		{
+			name:  "valid",
+			attrs: map[string]string{"project": "apollo", "pages": "12", "reviewed": "true", "due": "2026-01-31", "status": "final"},
+		},
+		{
+			name:  "missing required",
+			attrs: map[string]string{"pages": "12"},
+			errs:  []string{`"project" is required`},
+		},
+		{
+			name:  "wrong types",
+			attrs: map[string]string{"project": "apollo", "pages": "twelve", "reviewed": "maybe", "due": "31.01.2026"},
+			errs:  []string{`"pages" must be a number`, `"reviewed" must be a bool`, `"due" must be a date`},
+		},
+		{
+			name:  "rules",
+			attrs: map[string]string{"project": "apollo", "pages": "1001", "status": "wip"},
+			errs:  []string{`"pages" does not satisfy "max=1000"`, `"status"`},
+		},
+		{
+			name:  "unknown key",
+			attrs: map[string]string{"project": "apollo", "owner": "me"},
+		},
+		{
+			name:   "unknown key strict",
+			strict: true,
+			attrs:  map[string]string{"project": "apollo", "owner": "me"},
+			errs:   []string{`"owner" is not allowed`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema.Strict = tt.strict
+			err := schema.Validate(v, tt.attrs)
+			if len(tt.errs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tt.errs {
+				require.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
+
+func TestAttrSchemaCheckRules(t *testing.T) {
+	v := newAttrValidator(t)
+
+	valid := AttrSchema{Fields: []AttrField{{Key: "status", Type: AttrString, Rules: "oneof=draft final"}}}
+	require.NoError(t, valid.CheckRules(v))
+
+	invalid := AttrSchema{Fields: []AttrField{{Key: "status", Rules: "nosuchrule"}}}
+	require.ErrorContains(t, invalid.CheckRules(v), `"status"`)
+
+	mistyped := AttrSchema{Fields: []AttrField{{Key: "pages", Type: AttrNumber, Rules: "max=many"}}}
+	require.Error(t, mistyped.CheckRules(v))
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"github.com/mbretter/go-mongodb/types"
+	"time"
+)
+
+// Actions of the audit trail, named <kind>.<operation>.
+const (
+	AuditFileCreate     = "file.create"
+	AuditFileOpen       = "file.open"
+	AuditFileUpdate     = "file.update"
+	AuditFileCommit     = "file.commit"
+	AuditFileMove       = "file.move"
+	AuditFileRename     = "file.rename"
+	AuditFileStar       = "file.star"
+	AuditFileShare      = "file.share"
+	AuditFileTag        = "file.tag"
+	AuditFileUntag      = "file.untag"
+	AuditFileAttrs      = "file.attrs"
+	AuditFileLock       = "file.lock"
+	AuditFileUnlock     = "file.unlock"
+	AuditFileDelete     = "file.delete"
+	AuditDirCreate      = "directory.create"
+	AuditDirOpen        = "directory.open"
+	AuditDirMove        = "directory.move"
+	AuditDirRename      = "directory.rename"
+	AuditDirStar        = "directory.star"
+	AuditDirShare       = "directory.share"
+	AuditDirTag         = "directory.tag"
+	AuditDirUntag       = "directory.untag"
+	AuditDirDelete      = "directory.delete"
+	AuditTokenCreate    = "token.create"
+	AuditTokenRevoke    = "token.revoke"
+	AuditAdminTransfer  = "admin.transfer"
+	AuditAdminPurge     = "admin.purge"
+	AuditAdminResize    = "admin.resize"
+	AuditFSCallback     = "fs.callback"
+	AuditFSCallbackLost = "fs.callback.lost"
+)
+
+// AuditValues are the fields an action changed, before and after it.
+type AuditValues map[string]any
+
+// AuditRecord is an entry of the audit trail, records are never changed once written.
+type AuditRecord struct {
+	ID        types.ObjectId   `json:"id" bson:"_id,omitempty"`
+	TenantID  string           `json:"tenantID" bson:"tenantID"`
+	Actor     string           `json:"actor" bson:"actor"`
+	Action    string           `json:"action" bson:"action"`
+	Targets   []types.ObjectId `json:"targets" bson:"targets"`
+	Before    AuditValues      `json:"before,omitempty" bson:"before,omitempty"`
+	After     AuditValues      `json:"after,omitempty" bson:"after,omitempty"`
+	ClientIP  string           `json:"clientIP,omitempty" bson:"clientIP,omitempty"`
+	RequestID string           `json:"requestID,omitempty" bson:"requestID,omitempty"`
+	CreatedAt time.Time        `json:"createdAt" bson:"createdAt"`
+}
+
+// AuditQuery selects records of the audit trail, zero fields match every record.
+type AuditQuery struct {
+	Actor  string
+	Target types.ObjectId
+	Action string
+	From   time.Time
+	To     time.Time
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrRevisionMismatch is returned by the storage when the document was changed since the request checked its revision.
+var ErrRevisionMismatch = errors.New("revision mismatch")
+
+// ETag formats a revision as an HTTP entity tag.
+func ETag(revision uint64) string {
+	return `"` + strconv.FormatUint(revision, 10) + `"`
+}
+
+// MatchETag reports whether an If-Match or If-None-Match header lists the tag. "*" matches every tag,
+// weak tags are compared like strong ones.
+func MatchETag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Directory) ETag() string {
+	return ETag(d.Revision)
+}
+
+func (f *File) ETag() string {
+	return ETag(f.Revision)
+}
@@ -0,0 +1,19 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchETag(t *testing.T) {
+	tag := ETag(7)
+
+	require.Equal(t, `"7"`, tag)
+	require.True(t, MatchETag(`"7"`, tag))
+	require.True(t, MatchETag(`W/"7"`, tag))
+	require.True(t, MatchETag(`"5", "7"`, tag))
+	require.True(t, MatchETag(`*`, tag))
+	require.False(t, MatchETag(`"8"`, tag))
+	require.False(t, MatchETag(`7`, tag))
+}
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"github.com/go-playground/validator/v10"
+	"github.com/mbretter/go-mongodb/types"
 	"go.mongodb.org/mongo-driver/bson"
-	"regexp"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -15,25 +18,43 @@ const (
 )
 
 type Filter struct {
-	Type          Type      `query:"type" validate:"-"` // uint: 0 for both (file and directory), 1 for files only, 2 for directories only
-	Name          string    `query:"name" validate:"-"`
-	CreatedAtFrom time.Time `query:"createdAtFrom" validate:"-"`
-	CreatedAtTo   time.Time `query:"createdAtTo" validate:"-"`
-	UpdatedAtFrom time.Time `query:"updatedAtFrom" validate:"-"`
-	UpdatedAtTo   time.Time `query:"updatedAtTo" validate:"-"`
-	Public        *bool     `query:"public" validate:"-"`
-	Size          *uint     `query:"size" validate:"-"`
-	Starred       *bool     `query:"starred" validate:"-"`
+	Type          Type      `json:"type" bson:"type" query:"type" validate:"-"`                     // uint: 0 for both (file and directory), 1 for files only, 2 for directories only
+	Name          string    `json:"name,omitempty" bson:"name,omitempty" query:"name" validate:"-"` // full-text query, matched by the search.Index instead of ToMongoFilters
+	CreatedAtFrom time.Time `json:"createdAtFrom,omitzero" bson:"createdAtFrom,omitempty" query:"createdAtFrom" validate:"-"`
+	CreatedAtTo   time.Time `json:"createdAtTo,omitzero" bson:"createdAtTo,omitempty" query:"createdAtTo" validate:"-"`
+	UpdatedAtFrom time.Time `json:"updatedAtFrom,omitzero" bson:"updatedAtFrom,omitempty" query:"updatedAtFrom" validate:"-"`
+	UpdatedAtTo   time.Time `json:"updatedAtTo,omitzero" bson:"updatedAtTo,omitempty" query:"updatedAtTo" validate:"-"`
+	Public        *bool     `json:"public,omitempty" bson:"public,omitempty" query:"public" validate:"-"`
+	Size          *uint     `json:"size,omitempty" bson:"size,omitempty" query:"size" validate:"-"`
+	Starred       *bool     `json:"starred,omitempty" bson:"starred,omitempty" query:"starred" validate:"-"`
+	SizeMin       *uint     `json:"sizeMin,omitempty" bson:"sizeMin,omitempty" query:"sizeMin" validate:"-"`
+	SizeMax       *uint     `json:"sizeMax,omitempty" bson:"sizeMax,omitempty" query:"sizeMax" validate:"-"`
+	Tags          []string  `json:"tags,omitempty" bson:"tags,omitempty" query:"tags" validate:"-"` // names of tags, all must be attached
+	// In limits the search to the subtree of the directory, Subtree holds the IDs of all directories inside it
+	// and is resolved by the service, as files do not store their path
+	In      types.ObjectId   `json:"in,omitempty" bson:"in,omitempty" query:"in" validate:"-"`
+	Subtree []types.ObjectId `json:"-" bson:"-" query:"-" validate:"-"`
+	// InPath is an absolute path like /projects/2026 set by ApplyQuery, the service resolves it to In
+	InPath string `json:"-" bson:"-" query:"-" validate:"-"`
 	// Files only
-	Extensions []string `query:"extensions" validate:"-"`
+	Extensions         []string `json:"extensions,omitempty" bson:"extensions,omitempty" query:"extensions" validate:"-"`
+	ExcludedExtensions []string `json:"excludedExtensions,omitempty" bson:"excludedExtensions,omitempty" query:"excludedExtensions" validate:"-"`
+	Groups             []string `json:"groups,omitempty" bson:"groups,omitempty" query:"groups" validate:"dive,mimegroup"`  // see MimeGroups
+	Attrs              []string `json:"attrs,omitempty" bson:"attrs,omitempty" query:"attrs" validate:"dive,attrpredicate"` // "key:value" or "key" for existence
+	Hash               string   `json:"hash,omitempty" bson:"hash,omitempty" query:"hash" validate:"omitempty,sha256"`
+	MimeTypes          []string `json:"mimeTypes,omitempty" bson:"mimeTypes,omitempty" query:"mimeTypes" validate:"dive,mimepattern"` // "application/pdf" or "image/*"
 }
 
-func (f *Filter) ToMongoFilters() (directoriesFilter bson.D, filesFilter bson.D) {
-	if f.Name != "" {
-		directoriesFilter = append(directoriesFilter, bson.E{"name", bson.D{{"$regex", ".*" + regexp.QuoteMeta(f.Name) + ".*"}}})
-		filesFilter = append(filesFilter, bson.E{"name", bson.D{{"$regex", ".*" + regexp.QuoteMeta(f.Name) + ".*"}}})
-	}
+// MimeGroups maps the groups accepted by Filter.Groups to the extensions belonging to them.
+var MimeGroups = map[string][]string{
+	"images":    {"png", "jpg", "jpeg", "gif", "bmp", "webp", "svg", "tiff", "heic", "ico"},
+	"documents": {"pdf", "doc", "docx", "odt", "rtf", "txt", "md", "xls", "xlsx", "ods", "csv", "ppt", "pptx", "odp"},
+	"video":     {"mp4", "mkv", "mov", "avi", "webm", "wmv", "flv", "m4v"},
+	"audio":     {"mp3", "wav", "flac", "ogg", "aac", "m4a", "opus"},
+	"archives":  {"zip", "rar", "7z", "tar", "gz", "bz2", "xz"},
+}
 
+func (f *Filter) ToMongoFilters() (directoriesFilter bson.D, filesFilter bson.D) {
 	if !f.CreatedAtTo.IsZero() {
 		directoriesFilter = append(directoriesFilter,
 			bson.E{"createdAt", bson.D{{"$gte", f.CreatedAtFrom}, {"$lte", f.CreatedAtTo}}},
@@ -71,19 +92,79 @@ func (f *Filter) ToMongoFilters() (directoriesFilter bson.D, filesFilter bson.D)
 		filesFilter = append(filesFilter, bson.E{"public", *f.Public})
 	}
 
-	if f.Size != nil {
-		directoriesFilter = append(directoriesFilter, bson.E{"size", *f.Size})
-		filesFilter = append(filesFilter, bson.E{"size", *f.Size})
-	}
-
 	if f.Starred != nil {
 		directoriesFilter = append(directoriesFilter, bson.E{"starred", *f.Starred})
 		filesFilter = append(filesFilter, bson.E{"starred", *f.Starred})
 	}
 
-	if len(f.Extensions) != 0 {
+	var size bson.D
+	if f.Size != nil {
+		size = append(size, bson.E{"$eq", *f.Size})
+	}
+	if f.SizeMin != nil {
+		size = append(size, bson.E{"$gte", *f.SizeMin})
+	}
+	if f.SizeMax != nil {
+		size = append(size, bson.E{"$lte", *f.SizeMax})
+	}
+	if size != nil {
+		directoriesFilter = append(directoriesFilter, bson.E{"size", size})
+		filesFilter = append(filesFilter, bson.E{"size", size})
+	}
+
+	if len(f.Tags) != 0 {
+		directoriesFilter = append(directoriesFilter, bson.E{"tags.name", bson.D{{"$all", f.Tags}}})
+		filesFilter = append(filesFilter, bson.E{"tags.name", bson.D{{"$all", f.Tags}}})
+	}
+
+	if !f.In.IsZero() {
+		subtree := f.Subtree
+		if len(subtree) == 0 {
+			subtree = []types.ObjectId{f.In}
+		}
+		parentIDs := make([]string, len(subtree))
+		for num, id := range subtree {
+			parentIDs[num] = string(id)
+		}
+
+		directoriesFilter = append(directoriesFilter, bson.E{"path._id", f.In})
+		filesFilter = append(filesFilter, bson.E{"parentDirectoryID", bson.D{{"$in", parentIDs}}})
+	}
+
+	var extension bson.D
+	if extensions, ok := f.allowedExtensions(); ok {
 		directoriesFilter = nil
-		filesFilter = append(filesFilter, bson.E{"extension", bson.D{{"$in", f.Extensions}}})
+		extension = append(extension, bson.E{"$in", extensions})
+	}
+	if len(f.ExcludedExtensions) != 0 {
+		extension = append(extension, bson.E{"$nin", f.ExcludedExtensions})
+	}
+	if extension != nil {
+		filesFilter = append(filesFilter, bson.E{"extension", extension})
+	}
+
+	if len(f.Attrs) != 0 {
+		directoriesFilter = nil
+		predicates := make([]bson.D, len(f.Attrs))
+		for num, predicate := range f.Attrs {
+			key, value, ok := strings.Cut(predicate, ":")
+			if ok {
+				predicates[num] = bson.D{{"attrs." + key, value}}
+			} else {
+				predicates[num] = bson.D{{"attrs." + key, bson.D{{"$exists", true}}}}
+			}
+		}
+		filesFilter = append(filesFilter, bson.E{"$and", predicates})
+	}
+
+	if len(f.MimeTypes) != 0 {
+		directoriesFilter = nil
+		filesFilter = append(filesFilter, bson.E{"mimeType", bson.D{{"$in", mimePatterns(f.MimeTypes)}}})
+	}
+
+	if f.Hash != "" {
+		directoriesFilter = nil
+		filesFilter = append(filesFilter, bson.E{"hash", f.Hash})
 	}
 
 	if f.Type == FilesOnly {
@@ -95,3 +176,50 @@ func (f *Filter) ToMongoFilters() (directoriesFilter bson.D, filesFilter bson.D)
 
 	return directoriesFilter, filesFilter
 }
+
+// allowedExtensions intersects Extensions with the extensions of Groups.
+// It returns false when neither of them is set.
+func (f *Filter) allowedExtensions() ([]string, bool) {
+	if len(f.Extensions) == 0 && len(f.Groups) == 0 {
+		return nil, false
+	}
+
+	var grouped []string
+	for _, group := range f.Groups {
+		grouped = append(grouped, MimeGroups[group]...)
+	}
+	if len(f.Extensions) == 0 {
+		return grouped, true
+	}
+	if len(f.Groups) == 0 {
+		return f.Extensions, true
+	}
+
+	result := []string{}
+	for _, extension := range f.Extensions {
+		if slices.Contains(grouped, extension) {
+			result = append(result, extension)
+		}
+	}
+
+	return result, true
+}
+
+// ValidateMimeGroup is a validator.Func for the "mimegroup" tag.
+func ValidateMimeGroup(fl validator.FieldLevel) bool {
+	_, ok := MimeGroups[fl.Field().String()]
+
+	return ok
+}
+
+// ValidateAttrPredicate is a validator.Func for the "attrpredicate" tag.
+// The key must be usable as a MongoDB field name.
+func ValidateAttrPredicate(fl validator.FieldLevel) bool {
+	key, _, _ := strings.Cut(fl.Field().String(), ":")
+
+	return ValidAttrKey(key)
+}
+
+func ValidAttrKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, "$") && !strings.Contains(key, ".")
+}
@@ -0,0 +1,285 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func lookup(d bson.D, key string) (any, bool) {
+	for _, e := range d {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// filterOption is a single predicate of Filter together with the elements it must produce.
+type filterOption struct {
+	name  string
+	apply func(f *Filter)
+	// noDirectories is true when the predicate only applies to files
+	noDirectories bool
+	check         func(t *testing.T, directories, files bson.D, only bool)
+}
+
+func equalElement(key string, value any) func(t *testing.T, directories, files bson.D, only bool) {
+	return func(t *testing.T, directories, files bson.D, only bool) {
+		for _, d := range []bson.D{directories, files} {
+			if d == nil {
+				continue
+			}
+			got, ok := lookup(d, key)
+			require.True(t, ok, "%v not found in %v", key, d)
+			require.Equal(t, value, got)
+		}
+	}
+}
+
+func filesElement(key string, check func(t *testing.T, value any, only bool)) func(t *testing.T, directories, files bson.D, only bool) {
+	return func(t *testing.T, directories, files bson.D, only bool) {
+		if files == nil {
+			return
+		}
+		got, ok := lookup(files, key)
+		require.True(t, ok, "%v not found in %v", key, files)
+		check(t, got, only)
+	}
+}
+
+var (
+	createdFrom = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdTo   = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	updatedFrom = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	updatedTo   = time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	inID        = types.ObjectId("65f000000000000000000001")
+	subtreeID   = types.ObjectId("65f000000000000000000002")
+	testHash    = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+)
+
+var filterOptions = []filterOption{
+	{
+		name:  "createdAt",
+		apply: func(f *Filter) { f.CreatedAtFrom, f.CreatedAtTo = createdFrom, createdTo },
+		check: equalElement("createdAt", bson.D{{"$gte", createdFrom}, {"$lte", createdTo}}),
+	},
+	{
+		name:  "updatedAt",
+		apply: func(f *Filter) { f.UpdatedAtFrom, f.UpdatedAtTo = updatedFrom, updatedTo },
+		check: equalElement("updatedAt", bson.D{{"$gte", updatedFrom}, {"$lte", updatedTo}}),
+	},
+	{
+		name:  "public",
+		apply: func(f *Filter) { f.Public = ptr(true) },
+		check: equalElement("public", true),
+	},
+	{
+		name:  "starred",
+		apply: func(f *Filter) { f.Starred = ptr(false) },
+		check: equalElement("starred", false),
+	},
+	{
+		name:  "size",
+		apply: func(f *Filter) { f.Size = ptr(uint(10)) },
+		check: sizeOperator("$eq", uint(10)),
+	},
+	{
+		name:  "sizeMin",
+		apply: func(f *Filter) { f.SizeMin = ptr(uint(5)) },
+		check: sizeOperator("$gte", uint(5)),
+	},
+	{
+		name:  "sizeMax",
+		apply: func(f *Filter) { f.SizeMax = ptr(uint(20)) },
+		check: sizeOperator("$lte", uint(20)),
+	},
+	{
+		name:  "tags",
+		apply: func(f *Filter) { f.Tags = []string{"work", "urgent"} },
+		check: equalElement("tags.name", bson.D{{"$all", []string{"work", "urgent"}}}),
+	},
+	{
+		name:  "in",
+		apply: func(f *Filter) { f.In, f.Subtree = inID, []types.ObjectId{inID, subtreeID} },
+		check: func(t *testing.T, directories, files bson.D, only bool) {
+			if directories != nil {
+				got, ok := lookup(directories, "path._id")
+				require.True(t, ok)
+				require.Equal(t, inID, got)
+			}
+			if files != nil {
+				got, ok := lookup(files, "parentDirectoryID")
+				require.True(t, ok)
+				require.Equal(t, bson.D{{"$in", []string{string(inID), string(subtreeID)}}}, got)
+			}
+		},
+	},
+	{
+		name:          "extensions",
+		apply:         func(f *Filter) { f.Extensions = []string{"pdf", "exe"} },
+		noDirectories: true,
+		check: filesElement("extension", func(t *testing.T, value any, only bool) {
+			in, ok := lookup(value.(bson.D), "$in")
+			require.True(t, ok)
+			require.Contains(t, in, "pdf")
+			if only {
+				require.Equal(t, []string{"pdf", "exe"}, in)
+			}
+		}),
+	},
+	{
+		name:          "excludedExtensions",
+		apply:         func(f *Filter) { f.ExcludedExtensions = []string{"tmp"} },
+		noDirectories: false,
+		check: filesElement("extension", func(t *testing.T, value any, only bool) {
+			nin, ok := lookup(value.(bson.D), "$nin")
+			require.True(t, ok)
+			require.Equal(t, []string{"tmp"}, nin)
+		}),
+	},
+	{
+		name:          "groups",
+		apply:         func(f *Filter) { f.Groups = []string{"documents"} },
+		noDirectories: true,
+		check: filesElement("extension", func(t *testing.T, value any, only bool) {
+			in, ok := lookup(value.(bson.D), "$in")
+			require.True(t, ok)
+			require.Contains(t, in, "pdf")
+			require.NotContains(t, in, "exe")
+			if only {
+				require.Equal(t, MimeGroups["documents"], in)
+			}
+		}),
+	},
+	{
+		name:          "mimeTypes",
+		apply:         func(f *Filter) { f.MimeTypes = []string{"image/*", "application/x-pdf"} },
+		noDirectories: true,
+		check: filesElement("mimeType", func(t *testing.T, value any, only bool) {
+			require.Equal(t, bson.D{{"$in", []any{primitive.Regex{Pattern: "^image/"}, "application/pdf"}}}, value)
+		}),
+	},
+	{
+		name:          "hash",
+		apply:         func(f *Filter) { f.Hash = testHash },
+		noDirectories: true,
+		check: filesElement("hash", func(t *testing.T, value any, only bool) {
+			require.Equal(t, testHash, value)
+		}),
+	},
+	{
+		name:          "attrs",
+		apply:         func(f *Filter) { f.Attrs = []string{"project:apollo", "reviewed"} },
+		noDirectories: true,
+		check: filesElement("$and", func(t *testing.T, value any, only bool) {
+			require.Equal(t, []bson.D{
+				{{"attrs.project", "apollo"}},
+				{{"attrs.reviewed", bson.D{{"$exists", true}}}},
+			}, value)
+		}),
+	},
+}
+
+func sizeOperator(operator string, value uint) func(t *testing.T, directories, files bson.D, only bool) {
+	return func(t *testing.T, directories, files bson.D, only bool) {
+		for _, d := range []bson.D{directories, files} {
+			if d == nil {
+				continue
+			}
+			size, ok := lookup(d, "size")
+			require.True(t, ok)
+			got, ok := lookup(size.(bson.D), operator)
+			require.True(t, ok, "%v not found in %v", operator, size)
+			require.Equal(t, value, got)
+		}
+	}
+}
+
+func TestFilterToMongoFiltersCombinations(t *testing.T) {
+	for _, filterType := range []Type{Both, FilesOnly, DirectoriesOnly} {
+		for mask := 0; mask < 1<<len(filterOptions); mask++ {
+			var (
+				f             = Filter{Type: filterType}
+				selected      []filterOption
+				noDirectories = filterType == FilesOnly
+			)
+			for num, option := range filterOptions {
+				if mask&(1<<num) != 0 {
+					option.apply(&f)
+					selected = append(selected, option)
+					noDirectories = noDirectories || option.noDirectories
+				}
+			}
+
+			directories, files := f.ToMongoFilters()
+
+			name := fmt.Sprintf("type=%v", filterType)
+			for _, option := range selected {
+				name += "," + option.name
+			}
+
+			if noDirectories {
+				require.Nil(t, directories, name)
+			} else {
+				require.NotNil(t, directories, name)
+			}
+			if filterType == DirectoriesOnly {
+				require.Nil(t, files, name)
+			} else {
+				require.NotNil(t, files, name)
+			}
+
+			for _, option := range selected {
+				option.check(t, directories, files, len(selected) == 1)
+			}
+		}
+	}
+}
+
+func TestFilterToMongoFiltersExtensionsAndGroups(t *testing.T) {
+	f := Filter{
+		Extensions:         []string{"pdf", "png", "exe"},
+		ExcludedExtensions: []string{"png"},
+		Groups:             []string{"documents", "images"},
+	}
+
+	directories, files := f.ToMongoFilters()
+
+	require.Nil(t, directories)
+	extension, ok := lookup(files, "extension")
+	require.True(t, ok)
+	require.Equal(t, bson.D{{"$in", []string{"pdf", "png"}}, {"$nin", []string{"png"}}}, extension)
+}
+
+func TestFilterToMongoFiltersInWithoutSubtree(t *testing.T) {
+	f := Filter{In: inID}
+
+	_, files := f.ToMongoFilters()
+
+	parent, ok := lookup(files, "parentDirectoryID")
+	require.True(t, ok)
+	require.Equal(t, bson.D{{"$in", []string{string(inID)}}}, parent)
+}
+
+func TestFilterToMongoFiltersDefaults(t *testing.T) {
+	f := Filter{}
+
+	directories, files := f.ToMongoFilters()
+
+	require.Equal(t, bson.D{
+		{"createdAt", bson.D{{"$gte", time.Time{}}}},
+		{"updatedAt", bson.D{{"$gte", time.Time{}}}},
+	}, directories)
+	require.Equal(t, directories, files)
+}
@@ -0,0 +1,52 @@
+package core
+
+import "time"
+
+type LockMode string
+
+const (
+	LockExclusive LockMode = "exclusive" // nobody else may lock or change the file
+	LockShared    LockMode = "shared"    // others may take shared locks, nobody may change the file
+)
+
+// Lock is a check-out of a file by a user, it is ignored after ExpiresAt.
+type Lock struct {
+	UserID    string    `json:"userID" bson:"userID"`
+	Mode      LockMode  `json:"mode" bson:"mode"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt" bson:"expiresAt"`
+}
+
+func (l Lock) Active(now time.Time) bool {
+	return l.ExpiresAt.After(now)
+}
+
+// ActiveLocks returns the locks which have not expired.
+func ActiveLocks(locks []Lock, now time.Time) []Lock {
+	var active []Lock
+	for _, lock := range locks {
+		if lock.Active(now) {
+			active = append(active, lock)
+		}
+	}
+
+	return active
+}
+
+// DropExpiredLocks removes the expired locks of the files, e.g. of the ones embedded in a directory.
+func DropExpiredLocks(files []File, now time.Time) {
+	for i := range files {
+		files[i].Locks = ActiveLocks(files[i].Locks, now)
+	}
+}
+
+// LockedFor reports whether a user other than userID holds an active lock, which forbids userID to change the file.
+func LockedFor(locks []Lock, userID string, now time.Time) bool {
+	for _, lock := range locks {
+		if lock.Active(now) && lock.UserID != userID {
+			return true
+		}
+	}
+
+	return false
+}
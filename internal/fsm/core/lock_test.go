@@ -0,0 +1,30 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockedFor(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	active := now.Add(time.Minute)
+	expired := now.Add(-time.Minute)
+
+	locks := []Lock{
+		{UserID: "a", Mode: LockShared, ExpiresAt: active},
+		{UserID: "b", Mode: LockExclusive, ExpiresAt: expired},
+	}
+
+	require.False(t, LockedFor(locks, "a", now))
+	require.True(t, LockedFor(locks, "b", now))
+	require.True(t, LockedFor(locks, "c", now))
+	require.False(t, LockedFor(locks, "c", now.Add(time.Hour)))
+	require.Equal(t, locks[:1], ActiveLocks(locks, now))
+
+	files := []File{{Locks: locks}, {}}
+	DropExpiredLocks(files, now)
+	require.Equal(t, locks[:1], files[0].Locks)
+	require.Empty(t, files[1].Locks)
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"mime"
+	"regexp"
+	"strings"
+)
+
+const DefaultMimeType = "application/octet-stream"
+
+// mimeTypes covers the extensions users upload most, so the result does not depend on
+// the mime.types files installed on the host. Other extensions fall back to the mime package.
+var mimeTypes = map[string]string{
+	"png":  "image/png",
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"gif":  "image/gif",
+	"bmp":  "image/bmp",
+	"webp": "image/webp",
+	"svg":  "image/svg+xml",
+	"tiff": "image/tiff",
+	"heic": "image/heic",
+	"ico":  "image/vnd.microsoft.icon",
+	"pdf":  "application/pdf",
+	"doc":  "application/msword",
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"odt":  "application/vnd.oasis.opendocument.text",
+	"rtf":  "text/rtf",
+	"txt":  "text/plain",
+	"md":   "text/markdown",
+	"xls":  "application/vnd.ms-excel",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	"csv":  "text/csv",
+	"ppt":  "application/vnd.ms-powerpoint",
+	"pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"odp":  "application/vnd.oasis.opendocument.presentation",
+	"mp4":  "video/mp4",
+	"mkv":  "video/x-matroska",
+	"mov":  "video/quicktime",
+	"avi":  "video/x-msvideo",
+	"webm": "video/webm",
+	"wmv":  "video/x-ms-wmv",
+	"flv":  "video/x-flv",
+	"m4v":  "video/x-m4v",
+	"mp3":  "audio/mpeg",
+	"wav":  "audio/wav",
+	"flac": "audio/flac",
+	"ogg":  "audio/ogg",
+	"aac":  "audio/aac",
+	"m4a":  "audio/x-m4a",
+	"opus": "audio/opus",
+	"zip":  "application/zip",
+	"rar":  "application/x-rar-compressed",
+	"7z":   "application/x-7z-compressed",
+	"tar":  "application/x-tar",
+	"gz":   "application/gzip",
+	"bz2":  "application/x-bzip2",
+	"xz":   "application/x-xz",
+	"json": "application/json",
+	"xml":  "text/xml",
+	"html": "text/html",
+}
+
+// MimeTypeByExtension returns the MIME type for an extension given without the leading dot.
+func MimeTypeByExtension(extension string) string {
+	extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+	if mimeType, ok := mimeTypes[extension]; ok {
+		return mimeType
+	}
+	if extension != "" {
+		if mimeType := NormalizeMimeType(mime.TypeByExtension("." + extension)); mimeType != "" {
+			return mimeType
+		}
+	}
+
+	return DefaultMimeType
+}
+
+// NormalizeMimeType strips parameters and resolves aliases known to mimetype,
+// e.g. "application/x-pdf; q=1" becomes "application/pdf". It returns "" for malformed types.
+func NormalizeMimeType(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil || !strings.Contains(mediaType, "/") {
+		return ""
+	}
+	if known := mimetype.Lookup(mediaType); known != nil {
+		mediaType, _, _ = mime.ParseMediaType(known.String())
+	}
+
+	return mediaType
+}
+
+// MimeCategory returns the group of MimeGroups the type belongs to, or "other".
+func MimeCategory(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "images"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "audio"
+	}
+
+	for _, category := range []string{"documents", "archives"} {
+		for _, extension := range MimeGroups[category] {
+			if mimeTypes[extension] == mimeType {
+				return category
+			}
+		}
+	}
+	if strings.HasPrefix(mimeType, "text/") {
+		return "documents"
+	}
+
+	return "other"
+}
+
+// mimePatterns converts the patterns accepted by ValidMimePattern to values for $in.
+func mimePatterns(patterns []string) []any {
+	values := make([]any, len(patterns))
+	for num, pattern := range patterns {
+		kind, subtype, _ := strings.Cut(strings.ToLower(pattern), "/")
+		if subtype == "*" {
+			values[num] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(kind+"/")}
+		} else {
+			values[num] = NormalizeMimeType(pattern)
+		}
+	}
+
+	return values
+}
+
+// ValidMimePattern reports whether pattern is a MIME type like "application/pdf" or a wildcard like "image/*".
+func ValidMimePattern(pattern string) bool {
+	kind, subtype, ok := strings.Cut(pattern, "/")
+	if !ok || kind == "" || subtype == "" || kind == "*" {
+		return false
+	}
+
+	return subtype == "*" || NormalizeMimeType(pattern) != ""
+}
+
+// ValidateMimePattern is a validator.Func for the "mimepattern" tag.
+func ValidateMimePattern(fl validator.FieldLevel) bool {
+	return ValidMimePattern(fl.Field().String())
+}
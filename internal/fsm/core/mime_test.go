@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMimeTypeByExtension(t *testing.T) {
+	require.Equal(t, "application/pdf", MimeTypeByExtension("pdf"))
+	require.Equal(t, "image/jpeg", MimeTypeByExtension(".JPG"))
+	require.Equal(t, DefaultMimeType, MimeTypeByExtension(""))
+	require.Equal(t, DefaultMimeType, MimeTypeByExtension("nosuchextension"))
+}
+
+func TestNormalizeMimeType(t *testing.T) {
+	require.Equal(t, "text/plain", NormalizeMimeType("text/plain; charset=utf-8"))
+	require.Equal(t, "application/pdf", NormalizeMimeType("application/x-pdf"))
+	require.Equal(t, "audio/wav", NormalizeMimeType("audio/x-wav"))
+	require.Equal(t, "", NormalizeMimeType("pdf"))
+}
+
+func TestMimeCategory(t *testing.T) {
+	for mimeType, category := range map[string]string{
+		"image/heic":         "images",
+		"video/mp4":          "video",
+		"audio/flac":         "audio",
+		"application/pdf":    "documents",
+		"text/csv":           "documents",
+		"application/zip":    "archives",
+		"application/x-xz":   "archives",
+		"application/json":   "other",
+		DefaultMimeType:      "other",
+		"chemical/x-pdbfile": "other",
+	} {
+		require.Equal(t, category, MimeCategory(mimeType), mimeType)
+	}
+}
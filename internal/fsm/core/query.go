@@ -0,0 +1,507 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mbretter/go-mongodb/types"
+)
+
+// QueryError points at the token of a search query that could not be parsed.
+type QueryError struct {
+	Offset int // byte offset of Token inside the query
+	Token  string
+	Msg    string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%v at position %v near %q", e.Msg, e.Offset, e.Token)
+}
+
+type queryOp string
+
+const (
+	opEq  queryOp = ":"
+	opLt  queryOp = "<"
+	opLte queryOp = "<="
+	opGt  queryOp = ">"
+	opGte queryOp = ">="
+)
+
+// queryTerm is a single "key<op>value" or a bare word of the query.
+type queryTerm struct {
+	offset  int
+	raw     string
+	negated bool
+	key     string
+	op      queryOp
+	value   string
+}
+
+type queryKey struct {
+	comparable bool // allows <, <=, > and >= besides ":"
+	negatable  bool // allows the "-key:value" form
+	apply      func(f *Filter, t queryTerm, now time.Time) error
+}
+
+// queryKeys lists the keys of the query language, e.g.
+//
+//	name:report ext:pdf,docx size>10MB modified:<2026-01-01 starred:true in:/projects
+//
+// Words without a key are matched as the name.
+var queryKeys = map[string]queryKey{
+	"name": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		f.Name = strings.TrimSpace(f.Name + " " + t.value)
+		return nil
+	}},
+	"ext": {negatable: true, apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		extensions, err := t.list()
+		if err != nil {
+			return err
+		}
+		if t.negated {
+			f.ExcludedExtensions = append(f.ExcludedExtensions, extensions...)
+		} else {
+			f.Extensions = append(f.Extensions, extensions...)
+		}
+		return nil
+	}},
+	"group": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		groups, err := t.list()
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			if _, ok := MimeGroups[group]; !ok {
+				return t.errorf("unknown group %q", group)
+			}
+		}
+		f.Groups = append(f.Groups, groups...)
+		return nil
+	}},
+	"tag": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		tags, err := t.list()
+		if err != nil {
+			return err
+		}
+		f.Tags = append(f.Tags, tags...)
+		return nil
+	}},
+	"mime": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		patterns, err := t.list()
+		if err != nil {
+			return err
+		}
+		for _, pattern := range patterns {
+			if !ValidMimePattern(pattern) {
+				return t.errorf("invalid MIME type %q", pattern)
+			}
+		}
+		f.MimeTypes = append(f.MimeTypes, patterns...)
+		return nil
+	}},
+	"hash": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		hash := strings.ToLower(t.value)
+		if !ValidHash(hash) {
+			return t.errorf("invalid SHA-256 hash %q", t.value)
+		}
+		f.Hash = hash
+		return nil
+	}},
+	"attr": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		key, value, ok := strings.Cut(t.value, "=")
+		if !ValidAttrKey(key) {
+			return t.errorf("invalid attribute name %q", key)
+		}
+		if ok {
+			f.Attrs = append(f.Attrs, key+":"+value)
+		} else {
+			f.Attrs = append(f.Attrs, key)
+		}
+		return nil
+	}},
+	"size": {comparable: true, apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		size, err := t.size()
+		if err != nil {
+			return err
+		}
+		switch t.op {
+		case opEq:
+			f.Size = &size
+		case opGt, opGte:
+			if t.op == opGt {
+				size++
+			}
+			f.SizeMin = &size
+		case opLt, opLte:
+			if t.op == opLt {
+				if size == 0 {
+					return t.errorf("size can not be less than 0")
+				}
+				size--
+			}
+			f.SizeMax = &size
+		}
+		return nil
+	}},
+	"created": {comparable: true, apply: func(f *Filter, t queryTerm, now time.Time) error {
+		return t.applyDate(&f.CreatedAtFrom, &f.CreatedAtTo, now)
+	}},
+	"modified": {comparable: true, apply: func(f *Filter, t queryTerm, now time.Time) error {
+		return t.applyDate(&f.UpdatedAtFrom, &f.UpdatedAtTo, now)
+	}},
+	"starred": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		return t.applyBool(&f.Starred)
+	}},
+	"public": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		return t.applyBool(&f.Public)
+	}},
+	"type": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		switch strings.ToLower(t.value) {
+		case "file", "files":
+			f.Type = FilesOnly
+		case "dir", "dirs", "directory", "directories", "folder", "folders":
+			f.Type = DirectoriesOnly
+		case "any", "all":
+			f.Type = Both
+		default:
+			return t.errorf("unknown type %q", t.value)
+		}
+		return nil
+	}},
+	"in": {apply: func(f *Filter, t queryTerm, _ time.Time) error {
+		if objectIDRegexp.MatchString(t.value) {
+			f.In = types.ObjectId(t.value)
+			return nil
+		}
+		if !strings.HasPrefix(t.value, "/") {
+			return t.errorf("expected directory ID or absolute path")
+		}
+		f.InPath = t.value
+		return nil
+	}},
+}
+
+var objectIDRegexp = regexp.MustCompile("^[0-9a-fA-F]{24}$")
+
+// ApplyQuery parses the query language and adds its predicates to f.
+// Relative dates such as "today" or "-7d" are resolved against now.
+// The returned error is a *QueryError.
+func (f *Filter) ApplyQuery(q string, now time.Time) error {
+	terms, err := scanQuery(q)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range terms {
+		if t.key == "" {
+			f.Name = strings.TrimSpace(f.Name + " " + t.value)
+			continue
+		}
+
+		key, ok := queryKeys[strings.ToLower(t.key)]
+		if !ok {
+			return t.errorf("unknown key %q", t.key)
+		}
+		if t.negated && !key.negatable {
+			return t.errorf("key %q can not be negated", t.key)
+		}
+		if t.op != opEq && !key.comparable {
+			return t.errorf("key %q only supports \":\"", t.key)
+		}
+		if t.value == "" {
+			return t.errorf("missing value for key %q", t.key)
+		}
+
+		if err := key.apply(f, t, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanQuery(q string) ([]queryTerm, error) {
+	var terms []queryTerm
+
+	for i := 0; i < len(q); {
+		if q[i] == ' ' || q[i] == '\t' || q[i] == '\n' || q[i] == '\r' {
+			i++
+			continue
+		}
+
+		start := i
+		t, end, err := scanTerm(q, start)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+		i = end
+	}
+
+	return terms, nil
+}
+
+// scanTerm reads the term starting at start and returns it with the offset right after it.
+func scanTerm(q string, start int) (queryTerm, int, error) {
+	t := queryTerm{offset: start}
+
+	i := start
+	if q[i] == '"' {
+		value, end, err := scanQuoted(q, i)
+		if err != nil {
+			return t, 0, err
+		}
+		t.value, t.raw = value, q[start:end]
+
+		return t, end, t.checkEnd(q, end)
+	}
+
+	keyStart := i
+	if q[i] == '-' {
+		i++
+	}
+	for i < len(q) && isKeyRune(rune(q[i])) {
+		i++
+	}
+	keyEnd := i
+
+	op, opEnd := scanOp(q, i)
+	if op == "" || keyEnd == keyStart || (keyEnd == keyStart+1 && q[keyStart] == '-') {
+		// a bare word
+		end := wordEnd(q, start)
+		t.value, t.raw = q[start:end], q[start:end]
+
+		return t, end, nil
+	}
+
+	t.key = q[keyStart:keyEnd]
+	if strings.HasPrefix(t.key, "-") {
+		t.negated = true
+		t.key = t.key[1:]
+	}
+	t.op = op
+
+	i = opEnd
+	if i < len(q) && q[i] == '"' {
+		value, end, err := scanQuoted(q, i)
+		if err != nil {
+			return t, 0, err
+		}
+		t.value, t.raw = value, q[start:end]
+
+		return t, end, t.checkEnd(q, end)
+	}
+
+	end := wordEnd(q, i)
+	t.value, t.raw = q[i:end], q[start:end]
+	if strings.ContainsAny(t.value, "<>") {
+		return t, 0, t.errorf("unexpected operator in value")
+	}
+
+	return t, end, nil
+}
+
+// scanOp reads one of ":", ":<", ":<=", ":>", ":>=", "<", "<=", ">" and ">=" at i.
+func scanOp(q string, i int) (queryOp, int) {
+	if i >= len(q) {
+		return "", i
+	}
+
+	var op queryOp
+	switch q[i] {
+	case ':':
+		op = opEq
+		i++
+		if i < len(q) && (q[i] == '<' || q[i] == '>') {
+			return scanOp(q, i)
+		}
+		return op, i
+	case '<':
+		op = opLt
+	case '>':
+		op = opGt
+	default:
+		return "", i
+	}
+	i++
+	if i < len(q) && q[i] == '=' {
+		op += "="
+		i++
+	}
+
+	return op, i
+}
+
+func scanQuoted(q string, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(q); i++ {
+		switch q[i] {
+		case '\\':
+			if i+1 < len(q) {
+				i++
+				b.WriteByte(q[i])
+			}
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(q[i])
+		}
+	}
+
+	return "", 0, &QueryError{Offset: start, Token: q[start:], Msg: "unterminated quote"}
+}
+
+func wordEnd(q string, i int) int {
+	for i < len(q) && q[i] != ' ' && q[i] != '\t' && q[i] != '\n' && q[i] != '\r' {
+		i++
+	}
+
+	return i
+}
+
+func isKeyRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || r == '_')
+}
+
+func (t queryTerm) checkEnd(q string, end int) error {
+	if end < len(q) && q[end] != ' ' && q[end] != '\t' && q[end] != '\n' && q[end] != '\r' {
+		return t.errorf("expected space after closing quote")
+	}
+
+	return nil
+}
+
+func (t queryTerm) errorf(format string, args ...any) error {
+	return &QueryError{Offset: t.offset, Token: t.raw, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (t queryTerm) list() ([]string, error) {
+	values := strings.Split(t.value, ",")
+	if slices.Contains(values, "") {
+		return nil, t.errorf("empty element in list")
+	}
+
+	return values, nil
+}
+
+func (t queryTerm) applyBool(field **bool) error {
+	value, err := strconv.ParseBool(t.value)
+	if err != nil {
+		return t.errorf("expected true or false")
+	}
+	*field = &value
+
+	return nil
+}
+
+var sizeUnits = map[string]uint64{
+	"":   1,
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+}
+
+func (t queryTerm) size() (uint, error) {
+	value := strings.ToLower(t.value)
+	numberEnd := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if numberEnd == -1 {
+		numberEnd = len(value)
+	}
+	if numberEnd == 0 {
+		return 0, t.errorf("expected size, e.g. 10MB")
+	}
+
+	unit, ok := sizeUnits[value[numberEnd:]]
+	if !ok {
+		return 0, t.errorf("unknown size unit %q", t.value[numberEnd:])
+	}
+	number, err := strconv.ParseUint(value[:numberEnd], 10, 64)
+	if err != nil || number > math.MaxUint64/unit || number*unit > math.MaxUint {
+		return 0, t.errorf("size is too big")
+	}
+
+	return uint(number * unit), nil
+}
+
+// applyDate sets the range of a date key. A whole day compared with ":" covers that day,
+// otherwise ":" means "since".
+func (t queryTerm) applyDate(from, to *time.Time, now time.Time) error {
+	date, day, err := parseQueryDate(t.value, now)
+	if err != nil {
+		return t.errorf("%v", err)
+	}
+
+	switch t.op {
+	case opEq:
+		*from = date
+		if day {
+			*to = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	case opGt:
+		if day {
+			date = date.AddDate(0, 0, 1)
+		}
+		*from = date
+	case opGte:
+		*from = date
+	case opLt:
+		*to = date.Add(-time.Nanosecond)
+	case opLte:
+		if day {
+			date = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		*to = date
+	}
+
+	return nil
+}
+
+var relativeDateRegexp = regexp.MustCompile(`^-(\d{1,4})([hdwmy])$`)
+
+// parseQueryDate parses absolute dates (2006-01-02 or RFC 3339), "today", "yesterday"
+// and relative dates like "-7d" (h, d, w, m for months and y). day reports whether the
+// result is a whole day, relative dates are points in time, so "modified:-7d" means "since".
+func parseQueryDate(value string, now time.Time) (date time.Time, day bool, err error) {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch strings.ToLower(value) {
+	case "today":
+		return startOfDay, true, nil
+	case "yesterday":
+		return startOfDay.AddDate(0, 0, -1), true, nil
+	}
+
+	if match := relativeDateRegexp.FindStringSubmatch(strings.ToLower(value)); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "h":
+			return now.Add(-time.Duration(n) * time.Hour), false, nil
+		case "d":
+			return startOfDay.AddDate(0, 0, -n), false, nil
+		case "w":
+			return startOfDay.AddDate(0, 0, -7*n), false, nil
+		case "m":
+			return startOfDay.AddDate(0, -n, 0), false, nil
+		default:
+			return startOfDay.AddDate(-n, 0, 0), false, nil
+		}
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return date, true, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, false, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("expected date like 2006-01-02, today or -7d")
+}
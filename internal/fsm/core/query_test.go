@@ -0,0 +1,153 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+var queryNow = time.Date(2026, 3, 15, 13, 30, 0, 0, time.UTC)
+
+func TestFilterApplyQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Filter
+	}{
+		{
+			query: `name:report ext:pdf,docx size>10MB modified:<2026-01-01 starred:true in:/projects`,
+			want: Filter{
+				Name:        "report",
+				Extensions:  []string{"pdf", "docx"},
+				SizeMin:     ptr(uint(10<<20 + 1)),
+				UpdatedAtTo: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+				Starred:     ptr(true),
+				InPath:      "/projects",
+			},
+		},
+		{
+			query: `annual "quarterly report" -ext:tmp type:file`,
+			want: Filter{
+				Name:               "annual quarterly report",
+				ExcludedExtensions: []string{"tmp"},
+				Type:               FilesOnly,
+			},
+		},
+		{
+			query: `size:>=1kb size<=2KB public:false group:images,video tag:work,urgent mime:image/*,application/pdf`,
+			want: Filter{
+				MimeTypes: []string{"image/*", "application/pdf"},
+				Tags:      []string{"work", "urgent"},
+				SizeMin:   ptr(uint(1024)),
+				SizeMax:   ptr(uint(2048)),
+				Public:    ptr(false),
+				Groups:    []string{"images", "video"},
+			},
+		},
+		{
+			query: `created:2026-02-01 attr:project=apollo attr:reviewed in:65f000000000000000000001`,
+			want: Filter{
+				CreatedAtFrom: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+				CreatedAtTo:   time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+				Attrs:         []string{"project:apollo", "reviewed"},
+				In:            types.ObjectId("65f000000000000000000001"),
+			},
+		},
+		{
+			query: `modified:-7d created:>yesterday`,
+			want: Filter{
+				UpdatedAtFrom: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+				CreatedAtFrom: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			query: `name:"a \"quoted\" name" size:0 hash:9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08`,
+			want: Filter{
+				Name: `a "quoted" name`,
+				Size: ptr(uint(0)),
+				Hash: testHash,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var f Filter
+			require.NoError(t, f.ApplyQuery(tt.query, queryNow))
+			require.Equal(t, tt.want, f)
+		})
+	}
+}
+
+func TestFilterApplyQueryErrors(t *testing.T) {
+	tests := []struct {
+		query  string
+		offset int
+		token  string
+	}{
+		{`name:report size>>10`, 12, `size>>10`},
+		{`color:red`, 0, `color:red`},
+		{`report starred:maybe`, 7, `starred:maybe`},
+		{`ext:pdf,,doc`, 0, `ext:pdf,,doc`},
+		{`size:10XB`, 0, `size:10XB`},
+		{`size:99999999999999999999`, 0, `size:99999999999999999999`},
+		{`size<0`, 0, `size<0`},
+		{`starred>true`, 0, `starred>true`},
+		{`-name:report`, 0, `-name:report`},
+		{`modified:last-week`, 0, `modified:last-week`},
+		{`in:projects`, 0, `in:projects`},
+		{`group:music`, 0, `group:music`},
+		{`attr:$where=1`, 0, `attr:$where=1`},
+		{`hash:abc`, 0, `hash:abc`},
+		{`mime:*/pdf`, 0, `mime:*/pdf`},
+		{`name: report`, 0, `name:`},
+		{`x name:"report`, 7, `"report`},
+		{`name:"report"x`, 0, `name:"report"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var f Filter
+			err := f.ApplyQuery(tt.query, queryNow)
+
+			var queryErr *QueryError
+			require.True(t, errors.As(err, &queryErr), "got %v", err)
+			require.Equal(t, tt.offset, queryErr.Offset)
+			require.Equal(t, tt.token, queryErr.Token)
+		})
+	}
+}
+
+func FuzzFilterApplyQuery(f *testing.F) {
+	for _, seed := range []string{
+		`name:report ext:pdf,docx size>10MB modified:<2026-01-01 starred:true in:/projects`,
+		`"quoted phrase" -ext:tmp type:dir`,
+		`size:>=1kb created:today attr:key=value`,
+		`name:"unterminated`,
+		`modified:-12m public:false group:images`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, query string) {
+		var filter Filter
+		err := filter.ApplyQuery(query, queryNow)
+		if err == nil {
+			return
+		}
+
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) {
+			t.Fatalf("unexpected error type %T: %v", err, err)
+		}
+		if queryErr.Offset < 0 || queryErr.Offset >= len(query) {
+			t.Fatalf("offset %v out of range for %q", queryErr.Offset, query)
+		}
+		if !strings.HasPrefix(query[queryErr.Offset:], queryErr.Token) {
+			t.Fatalf("token %q is not at offset %v of %q", queryErr.Token, queryErr.Offset, query)
+		}
+	})
+}
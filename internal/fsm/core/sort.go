@@ -0,0 +1,101 @@
+package core
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SortableFields maps the sort keys accepted from clients to the document fields they sort by.
+// "type" orders directories before files when ascending.
+var SortableFields = map[string]string{
+	"type":      "_type",
+	"name":      "name",
+	"createdAt": "createdAt",
+	"updatedAt": "updatedAt",
+	"size":      "size",
+	"starred":   "starred",
+	"public":    "public",
+	"extension": "extension",
+}
+
+// SearchSortableFields are the SortableFields of search results, which can also be sorted by
+// "relevance", the score only the search pipeline sets.
+var SearchSortableFields = func() map[string]string {
+	fields := maps.Clone(SortableFields)
+	fields["relevance"] = "_score"
+
+	return fields
+}()
+
+type SortKey struct {
+	Field string
+	Order int
+}
+
+type Sort []SortKey
+
+// ParseSort parses a comma separated list of sort keys, e.g. "type,-name".
+// Keys prefixed with "-" are sorted in the opposite direction of order.
+// Unknown keys are skipped, use ValidateSortFields or ValidateSearchSortFields to reject them beforehand.
+func ParseSort(fields string, order int) Sort {
+	if order == 0 {
+		order = 1
+	}
+
+	var sort Sort
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		fieldOrder := order
+		if strings.HasPrefix(field, "-") {
+			field = field[1:]
+			fieldOrder = -order
+		}
+
+		mongoField, ok := SearchSortableFields[field]
+		if !ok {
+			continue
+		}
+		sort = append(sort, SortKey{mongoField, fieldOrder})
+	}
+
+	return sort
+}
+
+// ToMongo returns the sort document. The _id is always appended as the last key,
+// so paging over equal values is stable.
+func (s Sort) ToMongo() bson.D {
+	result := make(bson.D, 0, len(s)+1)
+	for _, key := range s {
+		result = append(result, bson.E{key.Field, key.Order})
+	}
+
+	return append(result, bson.E{"_id", 1})
+}
+
+// ValidateSortFields is a validator.Func for the "sortfields" tag.
+func ValidateSortFields(fl validator.FieldLevel) bool {
+	return validSortFields(fl.Field().String(), SortableFields)
+}
+
+// ValidateSearchSortFields is a validator.Func for the "searchsortfields" tag.
+func ValidateSearchSortFields(fl validator.FieldLevel) bool {
+	return validSortFields(fl.Field().String(), SearchSortableFields)
+}
+
+func validSortFields(fields string, sortable map[string]string) bool {
+	if fields == "" {
+		return true
+	}
+
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimPrefix(strings.TrimSpace(field), "-")
+		if _, ok := sortable[field]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
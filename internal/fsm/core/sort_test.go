@@ -0,0 +1,64 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		fields string
+		order  int
+		want   Sort
+	}{
+		{"type,name", 1, Sort{{"_type", 1}, {"name", 1}}},
+		{"type,-size", -1, Sort{{"_type", -1}, {"size", 1}}},
+		{" name , createdAt ", 0, Sort{{"name", 1}, {"createdAt", 1}}},
+		{"name,unknown", 1, Sort{{"name", 1}}},
+		{"-relevance,name", 1, Sort{{"_score", -1}, {"name", 1}}},
+		{"", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fields, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseSort(tt.fields, tt.order))
+		})
+	}
+}
+
+func TestSortToMongo(t *testing.T) {
+	require.Equal(t, bson.D{{"_type", 1}, {"name", -1}, {"_id", 1}}, Sort{{"_type", 1}, {"name", -1}}.ToMongo())
+	require.Equal(t, bson.D{{"_id", 1}}, Sort(nil).ToMongo())
+}
+
+func TestValidateSortFields(t *testing.T) {
+	v := validator.New()
+	require.NoError(t, v.RegisterValidation("sortfields", ValidateSortFields))
+
+	for fields, valid := range map[string]bool{
+		"":                true,
+		"type,name":       true,
+		"-size, -starred": true,
+		"name,unknown":    false,
+		"-":               false,
+		"-relevance":      false,
+	} {
+		err := v.Var(fields, "sortfields")
+		if valid {
+			require.NoError(t, err, "%q", fields)
+		} else {
+			require.Error(t, err, "%q", fields)
+		}
+	}
+}
+
+func TestValidateSearchSortFields(t *testing.T) {
+	v := validator.New()
+	require.NoError(t, v.RegisterValidation("searchsortfields", ValidateSearchSortFields))
+
+	require.NoError(t, v.Var("-relevance,type,name", "searchsortfields"))
+	require.Error(t, v.Var("relevance,unknown", "searchsortfields"))
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"github.com/mbretter/go-mongodb/types"
+	"slices"
 	"time"
 )
 
@@ -13,6 +14,7 @@ type PathElement struct {
 type Directory struct {
 	ID                types.ObjectId `json:"id" bson:"_id,omitempty"`
 	UserID            string         `json:"userID" bson:"userID"`
+	TenantID          string         `json:"-" bson:"tenantID"`
 	ParentDirectoryID string         `bson:"parentDirectoryID" json:"parentDirectoryID,omitempty"`
 	Path              []PathElement  `json:"path" bson:"path"`
 	Name              string         `json:"name" bson:"name"`
@@ -24,12 +26,35 @@ type Directory struct {
 	Directories       []Directory    `json:"directories" bson:"directories"`
 	FilesCount        uint           `json:"filesCount" bson:"filesCount"`
 	Files             []File         `json:"files" bson:"files"`
-	Size              uint           `json:"size" bson:"size"`
+	ShortcutsCount    uint           `json:"shortcutsCount" bson:"shortcutsCount"`
+	Shortcuts         []Shortcut     `json:"shortcuts" bson:"shortcuts,omitempty"`
+	Size              uint           `json:"size" bson:"size"` // shortcuts do not count
+	Tags              []TagRef       `json:"tags" bson:"tags,omitempty"`
+	Revision          uint64         `json:"revision" bson:"revision"` // incremented by every change, see ETag
+	Keywords          []string       `json:"-" bson:"keywords,omitempty"`
+}
+
+// Within reports whether the directory is the directory with the ID or one of its descendants.
+func (d *Directory) Within(id types.ObjectId) bool {
+	if d.ID == id {
+		return true
+	}
+
+	return slices.ContainsFunc(d.Path, func(element PathElement) bool {
+		return element.ID == id
+	})
+}
+
+// DirectoryPage is a page of the entries of a directory, Items keeps their order across Directories, Files and Shortcuts.
+type DirectoryPage struct {
+	Directory `bson:",inline"`
+	Items     []ItemRef `json:"items,omitempty" bson:"items,omitempty"`
 }
 
 type File struct {
 	ID                types.ObjectId    `json:"id" bson:"_id,omitempty"`
 	UserID            string            `json:"userID" bson:"userID"`
+	TenantID          string            `json:"-" bson:"tenantID"`
 	ParentDirectoryID string            `json:"parentDirectoryID" bson:"parentDirectoryID"`
 	CreatedAt         time.Time         `json:"createdAt" bson:"createdAt"`
 	UpdatedAt         time.Time         `json:"updatedAt" bson:"updatedAt"`
@@ -38,7 +63,13 @@ type File struct {
 	Public            bool              `json:"public" bson:"public"`
 	Name              string            `json:"name" bson:"name"`
 	Extension         string            `json:"extension" bson:"extension"`
+	MimeType          string            `json:"mimeType" bson:"mimeType"` // derived from Extension, replaced by the type sniffed by the FS on commit
 	Attrs             map[string]string `json:"attrs" bson:"attrs"`
+	Hash              string            `json:"hash,omitempty" bson:"hash,omitempty"` // hex SHA-256 of the content, set when the FS commits an upload
+	Tags              []TagRef          `json:"tags" bson:"tags,omitempty"`
+	Locks             []Lock            `json:"locks,omitempty" bson:"locks,omitempty"`
+	Revision          uint64            `json:"revision" bson:"revision"` // incremented by every change, see ETag
+	Keywords          []string          `json:"-" bson:"keywords,omitempty"`
 }
 
 type DirectoryLike struct {
@@ -46,4 +77,158 @@ type DirectoryLike struct {
 	Directories      []Directory `json:"directories" bson:"directories"`
 	FilesCount       uint        `json:"filesCount" bson:"filesCount"`
 	Files            []File      `json:"files" bson:"files"`
+	Items            []ItemRef   `json:"items" bson:"items"`
+}
+
+const (
+	DirectoryItem = "dir"
+	FileItem      = "file"
+	ShortcutItem  = "shortcut"
+)
+
+// ItemRef keeps the order of a page mixing directories and files.
+type ItemRef struct {
+	ID   types.ObjectId `json:"id" bson:"_id"`
+	Type string         `json:"type" bson:"_type"`
+}
+
+const (
+	ShortcutResolved  = "resolved"
+	ShortcutDangling  = "dangling"  // the target was deleted
+	ShortcutForbidden = "forbidden" // the target is neither owned by the user nor public
+)
+
+// Shortcut is an entry of a directory pointing at a directory or a file elsewhere.
+// Status and one of Directory and File are filled when the shortcut is resolved.
+type Shortcut struct {
+	ID                types.ObjectId `json:"id" bson:"_id,omitempty"`
+	UserID            string         `json:"userID" bson:"userID"`
+	TenantID          string         `json:"-" bson:"tenantID"`
+	ParentDirectoryID string         `json:"parentDirectoryID" bson:"parentDirectoryID"`
+	Name              string         `json:"name" bson:"name"`
+	TargetID          types.ObjectId `json:"targetID" bson:"targetID"`
+	TargetType        string         `json:"targetType" bson:"targetType"` // DirectoryItem or FileItem
+	CreatedAt         time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt" bson:"updatedAt"`
+	Status            string         `json:"status,omitempty" bson:"-"`
+	Directory         *Directory     `json:"directory,omitempty" bson:"-"`
+	File              *File          `json:"file,omitempty" bson:"-"`
+}
+
+// SavedSearch is a named search of a user. Query is parsed again on every run,
+// so relative dates like "modified:-7d" move with time.
+type SavedSearch struct {
+	ID          types.ObjectId `json:"id" bson:"_id,omitempty"`
+	UserID      string         `json:"userID" bson:"userID"`
+	TenantID    string         `json:"-" bson:"tenantID"`
+	Name        string         `json:"name" bson:"name"`
+	Query       string         `json:"query" bson:"query"`
+	Filter      Filter         `json:"filter" bson:"filter"`
+	SortByField string         `json:"sortByField" bson:"sortByField"`
+	SortOrder   int            `json:"sortOrder" bson:"sortOrder"`
+	CreatedAt   time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+type Tag struct {
+	ID        types.ObjectId `json:"id" bson:"_id,omitempty"`
+	UserID    string         `json:"userID" bson:"userID"`
+	TenantID  string         `json:"-" bson:"tenantID"`
+	Name      string         `json:"name" bson:"name"`
+	Color     string         `json:"color" bson:"color"`
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+func (t *Tag) Ref() TagRef {
+	return TagRef{ID: t.ID, Name: t.Name, Color: t.Color}
+}
+
+// TagRef is the copy of a Tag embedded in directories and files.
+type TagRef struct {
+	ID    types.ObjectId `json:"id" bson:"_id"`
+	Name  string         `json:"name" bson:"name"`
+	Color string         `json:"color" bson:"color"`
+}
+
+// Duplicates are the files of a user sharing the same content hash.
+type Duplicates struct {
+	Hash  string `json:"hash" bson:"_id"`
+	Size  uint   `json:"size" bson:"size"`
+	Count uint   `json:"count" bson:"count"`
+	Files []File `json:"files" bson:"files"`
+}
+
+// ValidHash reports whether hash is a lowercase hex SHA-256 digest.
+func ValidHash(hash string) bool {
+	if len(hash) != 64 {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Usage is the storage used by a user, split by the categories of MimeCategory.
+type Usage struct {
+	Count      uint            `json:"count"`
+	Size       uint            `json:"size"`
+	Categories []CategoryUsage `json:"categories"`
+}
+
+// Totals is what is stored across all users and tenants.
+type Totals struct {
+	Files       uint
+	Directories uint
+	Bytes       uint
+}
+
+// UserUsage is what a user stores, as listed to admins.
+type UserUsage struct {
+	UserID      string         `json:"userID" bson:"userID"`
+	TenantID    string         `json:"tenantID" bson:"tenantID"`
+	RootID      types.ObjectId `json:"rootID" bson:"_id"`
+	Size        uint           `json:"size" bson:"size"`
+	Files       uint           `json:"files" bson:"files"`
+	Directories uint           `json:"directories" bson:"directories"`
+}
+
+// Recalculation is the result of recalculating the directory sizes of a user from the sizes of their files.
+type Recalculation struct {
+	Directories uint `json:"directories"`
+	// Corrected is the number of directories whose size was wrong
+	Corrected uint `json:"corrected"`
+	Size      uint `json:"size"`
+}
+
+type CategoryUsage struct {
+	Category string `json:"category"`
+	Count    uint   `json:"count"`
+	Size     uint   `json:"size"`
+}
+
+// MimeTypeUsage is the storage used by the files of a single MIME type.
+type MimeTypeUsage struct {
+	MimeType string `bson:"_id"`
+	Count    uint   `bson:"count"`
+	Size     uint   `bson:"size"`
+}
+
+// Activity is the last time a user opened or modified a file.
+type Activity struct {
+	UserID     string         `json:"userID" bson:"userID"`
+	TenantID   string         `json:"-" bson:"tenantID"`
+	FileID     types.ObjectId `json:"fileID" bson:"fileID"`
+	OpenedAt   time.Time      `json:"openedAt,omitzero" bson:"openedAt,omitempty"`
+	ModifiedAt time.Time      `json:"modifiedAt,omitzero" bson:"modifiedAt,omitempty"`
+}
+
+type RecentFile struct {
+	File       File      `json:"file" bson:"file"`
+	OpenedAt   time.Time `json:"openedAt,omitzero" bson:"openedAt,omitempty"`
+	ModifiedAt time.Time `json:"modifiedAt,omitzero" bson:"modifiedAt,omitempty"`
 }
@@ -0,0 +1,23 @@
+package core
+
+import "time"
+
+// Tenant is the configuration of an organization, taken from the tenant claim of the token.
+// Tenants without a stored configuration use DefaultTenant.
+type Tenant struct {
+	ID            string    `json:"id" bson:"_id"`
+	Name          string    `json:"name" bson:"name"`
+	UserQuota     uint      `json:"userQuota" bson:"userQuota"`         // bytes per user, 0 for unlimited
+	PublicSharing bool      `json:"publicSharing" bson:"publicSharing"` // users may make items public
+	Workspaces    bool      `json:"workspaces" bson:"workspaces"`       // users may create shared workspaces
+	CreatedAt     time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+func DefaultTenant(id string) *Tenant {
+	return &Tenant{
+		ID:            id,
+		PublicSharing: true,
+		Workspaces:    true,
+	}
+}
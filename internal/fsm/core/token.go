@@ -0,0 +1,48 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/mbretter/go-mongodb/types"
+	"time"
+)
+
+// APITokenPrefix starts the secret of every API token, which tells them apart from JWTs.
+const APITokenPrefix = "fsm_"
+
+// APIToken is a personal access token of a user for automation. Only the hash of its secret is stored,
+// the secret itself is returned once when the token is created.
+type APIToken struct {
+	ID       types.ObjectId `json:"id" bson:"_id,omitempty"`
+	UserID   string         `json:"userID" bson:"userID"`
+	TenantID string         `json:"-" bson:"tenantID"`
+	Name     string         `json:"name" bson:"name"`
+	Hash     string         `json:"-" bson:"hash"`
+	// Hint is the start of the secret, to recognize the token in lists
+	Hint   string   `json:"hint" bson:"hint"`
+	Scopes []string `json:"scopes" bson:"scopes"`
+	// DirectoryID is the subtree the token is limited to, zero if it is not limited
+	DirectoryID types.ObjectId `json:"directoryID,omitempty" bson:"directoryID,omitempty"`
+	ExpiresAt   time.Time      `json:"expiresAt" bson:"expiresAt"`
+	LastUsedAt  *time.Time     `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt" bson:"createdAt"`
+}
+
+// NewAPITokenSecret returns a random secret for an API token.
+func NewAPITokenSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return APITokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAPIToken returns the hex SHA-256 of the secret, the secrets are random enough to need no salt.
+func HashAPIToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
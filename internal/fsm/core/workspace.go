@@ -0,0 +1,85 @@
+package core
+
+import (
+	"github.com/mbretter/go-mongodb/types"
+	"strings"
+	"time"
+)
+
+// WorkspaceOwnerPrefix marks the UserID of directories and files owned by a workspace instead of a user.
+const WorkspaceOwnerPrefix = "ws:"
+
+type Role string
+
+const (
+	RoleOwner  Role = "owner"  // manages members and settings
+	RoleEditor Role = "editor" // changes the tree
+	RoleViewer Role = "viewer" // reads the tree
+)
+
+func (r Role) CanWrite() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+type Member struct {
+	UserID string `json:"userID" bson:"userID"`
+	Role   Role   `json:"role" bson:"role"`
+}
+
+// Workspace is a tree shared by its members. Its directories and files are owned by
+// WorkspaceOwner(ID) rather than by the user who created them.
+type Workspace struct {
+	ID        types.ObjectId `json:"id" bson:"_id,omitempty"`
+	TenantID  string         `json:"-" bson:"tenantID"`
+	Name      string         `json:"name" bson:"name"`
+	RootID    types.ObjectId `json:"rootID" bson:"rootID"`
+	Members   []Member       `json:"members" bson:"members"`
+	Quota     uint           `json:"quota" bson:"quota"` // bytes, 0 for unlimited
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Role returns the role of the user, false if the user is not a member.
+func (w *Workspace) Role(userID string) (Role, bool) {
+	for _, member := range w.Members {
+		if member.UserID == userID {
+			return member.Role, true
+		}
+	}
+
+	return "", false
+}
+
+// IsLastOwner reports whether the user is the only owner, who can not leave or be demoted.
+func (w *Workspace) IsLastOwner(userID string) bool {
+	if role, _ := w.Role(userID); role != RoleOwner {
+		return false
+	}
+
+	owners := 0
+	for _, member := range w.Members {
+		if member.Role == RoleOwner {
+			owners++
+		}
+	}
+
+	return owners == 1
+}
+
+func (w *Workspace) Owner() string {
+	return WorkspaceOwner(w.ID)
+}
+
+func WorkspaceOwner(id types.ObjectId) string {
+	return WorkspaceOwnerPrefix + string(id)
+}
+
+// ParseWorkspaceOwner returns the workspace owning items with the given UserID, false for items of users.
+func ParseWorkspaceOwner(owner string) (types.ObjectId, bool) {
+	id, ok := strings.CutPrefix(owner, WorkspaceOwnerPrefix)
+	if !ok {
+		return "", false
+	}
+
+	return types.ObjectId(id), true
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/admin"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type AdminService interface {
+	ListUsers(ctx owncontext.Context, data *admin.ListUsersRequest) (*[]core.UserUsage, error)
+	Tree(ctx owncontext.Context, data *admin.TreeRequest) (*core.DirectoryPage, error)
+	Transfer(ctx owncontext.Context, data *admin.TransferRequest) error
+	DeleteUser(ctx owncontext.Context, data *admin.DeleteUserRequest) error
+	Recalculate(ctx owncontext.Context, data *admin.RecalculateRequest) (*core.Recalculation, error)
+}
+
+type AdminHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service AdminService
+}
+
+func NewAdminHandler(l *slog.Logger, v *validator.Validate, adminService AdminService) *AdminHandler {
+	return &AdminHandler{
+		l:       l.With("module", "internal.fsm.handler.AdminHandler"),
+		v:       v,
+		service: adminService,
+	}
+}
+
+func (h *AdminHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/users", handler.NewWithResult(h.l, h.v, "ListUsers", handler.QueryInput, h.service.ListUsers).Handler())
+	api.Get("/users/:userID/tree", handler.NewWithResult(h.l, h.v, "Tree", handler.ParamAndQueryInput, h.service.Tree).Handler())
+	api.Post("/users/:userID/recalculate", handler.NewWithResult(h.l, h.v, "Recalculate", handler.ParamsInput, h.service.Recalculate).Handler())
+	api.Delete("/users/:userID", handler.NewWithoutResult(h.l, h.v, "DeleteUser", handler.ParamsInput, h.service.DeleteUser).Handler())
+	api.Post("/transfer", handler.NewWithoutResult(h.l, h.v, "Transfer", handler.BodyInput, h.service.Transfer).Handler())
+}
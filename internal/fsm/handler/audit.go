@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/audit"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"log/slog"
+)
+
+const jsonLinesContentType = "application/jsonl"
+
+type AuditService interface {
+	List(ctx owncontext.Context, data *audit.ListRequest) (*[]core.AuditRecord, error)
+	Export(ctx owncontext.Context, data *audit.ExportRequest, w io.Writer) error
+}
+
+type AuditHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service AuditService
+}
+
+func NewAuditHandler(l *slog.Logger, v *validator.Validate, auditService AuditService) *AuditHandler {
+	return &AuditHandler{
+		l:       l.With("module", "internal.fsm.handler.AuditHandler"),
+		v:       v,
+		service: auditService,
+	}
+}
+
+func (h *AuditHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.QueryInput, h.service.List).Handler())
+	api.Get("/export", handler.NewWithWriter(h.l, h.v, "Export", jsonLinesContentType, handler.QueryInput, h.service.Export).Handler())
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/ratelimit"
 	"github.com/StratuStore/fsm/internal/fsm/service/directory"
 	"github.com/StratuStore/fsm/internal/libs/handler"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
@@ -13,11 +14,13 @@ import (
 type DirectoryService interface {
 	Create(ctx owncontext.Context, data *directory.CreateRequest) (*core.Directory, error)
 	Delete(ctx owncontext.Context, data *directory.DeleteRequest) error
-	Get(ctx owncontext.Context, data *directory.GetRequest) (*core.Directory, error)
+	Get(ctx owncontext.Context, data *directory.GetRequest) (*core.DirectoryPage, error)
 	Rename(ctx owncontext.Context, data *directory.RenameRequest) error
 	Move(ctx owncontext.Context, data *directory.MoveRequest) error
 	Publicate(ctx owncontext.Context, data *directory.PublicateRequest) error
 	Star(ctx owncontext.Context, data *directory.StarRequest) error
+	AddTag(ctx owncontext.Context, data *directory.TagRequest) error
+	RemoveTag(ctx owncontext.Context, data *directory.TagRequest) error
 	Search(ctx owncontext.Context, data *directory.SearchRequest) (*core.DirectoryLike, error)
 }
 
@@ -25,25 +28,31 @@ type DirectoryHandler struct {
 	l       *slog.Logger
 	v       *validator.Validate
 	service DirectoryService
+	limiter *ratelimit.Limiter
 }
 
-func NewDirectoryHandler(l *slog.Logger, v *validator.Validate, directoryService DirectoryService) *DirectoryHandler {
+func NewDirectoryHandler(l *slog.Logger, v *validator.Validate, directoryService DirectoryService, limiter *ratelimit.Limiter) *DirectoryHandler {
 	return &DirectoryHandler{
 		l:       l.With("module", "internal.fsm.handler.DirectoryHandler"),
 		v:       v,
 		service: directoryService,
+		limiter: limiter,
 	}
 }
 
 func (h *DirectoryHandler) Register(app *fiber.App, subpath string) {
 	api := app.Group(subpath)
+	read := handler.RequireScope(handler.ScopeFilesRead)
+	write := handler.RequireScope(handler.ScopeFilesWrite)
 
-	api.Get("/search", handler.NewWithResult(h.l, h.v, "Search", handler.QueryInput, h.service.Search).Handler())
-	api.Get("/:id?", handler.NewWithResult(h.l, h.v, "Get", handler.ParamAndQueryInput, h.service.Get).Handler())
-	api.Patch("/:id/move", handler.NewWithoutResult(h.l, h.v, "Move", handler.ParamAndQueryInput, h.service.Move).Handler())
-	api.Post("/", handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
-	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Delete", handler.ParamsInput, h.service.Delete).Handler())
-	api.Patch("/:id/rename", handler.NewWithoutResult(h.l, h.v, "Rename", handler.ParamAndQueryInput, h.service.Rename).Handler())
-	api.Patch("/:id/share", handler.NewWithoutResult(h.l, h.v, "Publicate", handler.ParamAndQueryInput, h.service.Publicate).Handler())
-	api.Patch("/:id/star", handler.NewWithoutResult(h.l, h.v, "Star", handler.ParamsInput, h.service.Star).Handler())
+	api.Get("/search", read, h.limiter.Middleware(ratelimit.SearchGroup), handler.NewWithResult(h.l, h.v, "Search", handler.QueryInput, h.service.Search).Handler())
+	api.Get("/:id?", read, handler.NewWithResult(h.l, h.v, "Get", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Get).Handler())
+	api.Patch("/:id/move", write, handler.NewWithoutResult(h.l, h.v, "Move", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Move).Handler())
+	api.Post("/", write, handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
+	api.Delete("/:id", write, handler.NewWithoutResult(h.l, h.v, "Delete", handler.WithHeaders(handler.ParamsInput), h.service.Delete).Handler())
+	api.Patch("/:id/rename", write, handler.NewWithoutResult(h.l, h.v, "Rename", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Rename).Handler())
+	api.Patch("/:id/share", write, handler.NewWithoutResult(h.l, h.v, "Publicate", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Publicate).Handler())
+	api.Patch("/:id/star", write, handler.NewWithoutResult(h.l, h.v, "Star", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Star).Handler())
+	api.Put("/:id/tags/:tagID", write, handler.NewWithoutResult(h.l, h.v, "AddTag", handler.WithHeaders(handler.ParamsInput), h.service.AddTag).Handler())
+	api.Delete("/:id/tags/:tagID", write, handler.NewWithoutResult(h.l, h.v, "RemoveTag", handler.WithHeaders(handler.ParamsInput), h.service.RemoveTag).Handler())
 }
@@ -2,10 +2,16 @@ package handler
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/auth"
 	"github.com/StratuStore/fsm/internal/fsm/communicator"
+	"github.com/StratuStore/fsm/internal/fsm/health"
+	"github.com/StratuStore/fsm/internal/fsm/metrics"
+	"github.com/StratuStore/fsm/internal/fsm/ratelimit"
+	"github.com/StratuStore/fsm/internal/fsm/tracing"
 	"github.com/StratuStore/fsm/internal/libs/config"
-	jwtware "github.com/gofiber/contrib/jwt"
+	"github.com/StratuStore/fsm/internal/libs/handler"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"go.opentelemetry.io/otel/trace"
 	"log/slog"
 	"net"
 
@@ -14,13 +20,34 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
+type HealthChecker interface {
+	Report(ctx context.Context) *health.Report
+	Ready(ctx context.Context) bool
+}
+
 type Handler struct {
 	app              *fiber.App
 	l                *slog.Logger
 	cfg              *config.Config
 	fileHandler      *FileHandler
 	directoryHandler *DirectoryHandler
+	smartHandler     *SmartHandler
+	tagHandler       *TagHandler
+	schemaHandler    *SchemaHandler
+	recentHandler    *RecentHandler
+	starredHandler   *StarredHandler
+	shortcutHandler  *ShortcutHandler
+	workspaceHandler *WorkspaceHandler
+	tenantHandler    *TenantHandler
+	auditHandler     *AuditHandler
+	tokenHandler     *TokenHandler
+	adminHandler     *AdminHandler
 	comm             *communicator.Communicator
+	m                *metrics.Metrics
+	tp               trace.TracerProvider
+	health           HealthChecker
+	limiter          *ratelimit.Limiter
+	auth             *auth.Authenticator
 }
 
 func New(
@@ -28,7 +55,23 @@ func New(
 	cfg *config.Config,
 	fileHandler *FileHandler,
 	directoryHandler *DirectoryHandler,
+	smartHandler *SmartHandler,
+	tagHandler *TagHandler,
+	schemaHandler *SchemaHandler,
+	recentHandler *RecentHandler,
+	starredHandler *StarredHandler,
+	shortcutHandler *ShortcutHandler,
+	workspaceHandler *WorkspaceHandler,
+	tenantHandler *TenantHandler,
+	auditHandler *AuditHandler,
+	tokenHandler *TokenHandler,
+	adminHandler *AdminHandler,
 	comm *communicator.Communicator,
+	m *metrics.Metrics,
+	tp trace.TracerProvider,
+	healthChecker HealthChecker,
+	limiter *ratelimit.Limiter,
+	authenticator *auth.Authenticator,
 ) *Handler {
 	h := &Handler{
 		app: fiber.New(fiber.Config{
@@ -40,7 +83,23 @@ func New(
 		cfg:              cfg,
 		fileHandler:      fileHandler,
 		directoryHandler: directoryHandler,
+		smartHandler:     smartHandler,
+		tagHandler:       tagHandler,
+		schemaHandler:    schemaHandler,
+		recentHandler:    recentHandler,
+		starredHandler:   starredHandler,
+		shortcutHandler:  shortcutHandler,
+		workspaceHandler: workspaceHandler,
+		tenantHandler:    tenantHandler,
+		auditHandler:     auditHandler,
+		tokenHandler:     tokenHandler,
+		adminHandler:     adminHandler,
 		comm:             comm,
+		m:                m,
+		tp:               tp,
+		health:           healthChecker,
+		limiter:          limiter,
+		auth:             authenticator,
 	}
 
 	h.Register()
@@ -51,12 +110,32 @@ func New(
 func (h *Handler) Register() {
 	h.registerDefaults()
 
+	// the callbacks of the FS answer requests of the service itself, they are not limited
+	h.app.Post("/communicate", h.comm.Handler)
+	h.app.Use(h.limiter.Middleware(ratelimit.DefaultGroup))
+
 	h.fileHandler.Register(h.app, "/file")
 	h.directoryHandler.Register(h.app, "/directory")
-	h.app.Post("/communicate", h.comm.Handler)
+	// API tokens only reach the routes registered above
+	h.app.Use(handler.DenyAPITokens())
+	h.smartHandler.Register(h.app, "/smart")
+	h.tagHandler.Register(h.app, "/tag")
+	h.schemaHandler.Register(h.app, "/schema")
+	h.recentHandler.Register(h.app, "/recent")
+	h.starredHandler.Register(h.app, "/starred")
+	h.shortcutHandler.Register(h.app, "/shortcut")
+	h.workspaceHandler.Register(h.app, "/workspace")
+	h.tenantHandler.Register(h.app, "/tenant")
+	h.auditHandler.Register(h.app, "/audit")
+	h.tokenHandler.Register(h.app, "/token")
+	h.adminHandler.Register(h.app, "/admin")
 }
 
 func (h *Handler) registerDefaults() {
+	h.app.Use(handler.RequestID())
+	h.app.Use(tracing.Middleware(h.tp))
+	h.app.Use(h.m.Middleware())
+
 	if h.cfg.Env == "dev" {
 		h.app.Use(cors.New(cors.ConfigDefault))
 	} else {
@@ -77,17 +156,28 @@ func (h *Handler) registerDefaults() {
 		},
 		LivenessEndpoint: "/live",
 		ReadinessProbe: func(c *fiber.Ctx) bool {
-			return true // TODO: service check
+			return h.health.Ready(c.UserContext())
 		},
 		ReadinessEndpoint: "/ready",
 	}))
 
-	h.app.Use(jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{
-			JWTAlg: jwtware.HS512,
-			Key:    []byte(h.cfg.JWTSecret),
-		},
-	}))
+	// scraped without a token, like the probes
+	h.app.Get("/metrics", h.m.Handler())
+	h.app.Get("/health", h.healthReport)
+
+	h.app.Use(h.auth.Middleware())
+}
+
+// healthReport responds with the result of every check, with 503 if the service is down.
+func (h *Handler) healthReport(c *fiber.Ctx) error {
+	report := h.health.Report(c.UserContext())
+
+	status := fiber.StatusOK
+	if report.Status == health.StatusDown {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(report)
 }
 
 func (h *Handler) Start(_ context.Context) error {
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/ratelimit"
 	"github.com/StratuStore/fsm/internal/fsm/service/file"
 	"github.com/StratuStore/fsm/internal/libs/handler"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
@@ -16,33 +18,51 @@ type FileService interface {
 	Delete(ctx owncontext.Context, data *file.DeleteRequest) error
 	Rename(ctx owncontext.Context, data *file.RenameRequest) error
 	Update(ctx owncontext.Context, data *file.UpdateRequest) (*file.UpdateResponse, error)
-	Star(ctx owncontext.Context, data *file.GetRequest) error
+	Star(ctx owncontext.Context, data *file.StarRequest) error
+	AddTag(ctx owncontext.Context, data *file.TagRequest) error
+	RemoveTag(ctx owncontext.Context, data *file.TagRequest) error
 	Publicate(ctx owncontext.Context, data *file.PublicateRequest) error
+	UpdateAttrs(ctx owncontext.Context, data *file.AttrsRequest) (*core.File, error)
+	Duplicates(ctx owncontext.Context, data *file.DuplicatesRequest) (*[]core.Duplicates, error)
+	Usage(ctx owncontext.Context, data *file.UsageRequest) (*core.Usage, error)
+	Lock(ctx owncontext.Context, data *file.LockRequest) (*core.Lock, error)
+	Unlock(ctx owncontext.Context, data *file.UnlockRequest) error
 }
 
 type FileHandler struct {
 	l       *slog.Logger
 	v       *validator.Validate
 	service FileService
+	limiter *ratelimit.Limiter
 }
 
-func NewFileHandler(l *slog.Logger, v *validator.Validate, fileService FileService) *FileHandler {
+func NewFileHandler(l *slog.Logger, v *validator.Validate, fileService FileService, limiter *ratelimit.Limiter) *FileHandler {
 	return &FileHandler{
 		l:       l.With("module", "internal.fsm.handler.FileHandler"),
 		v:       v,
 		service: fileService,
+		limiter: limiter,
 	}
 }
 
 func (h *FileHandler) Register(app *fiber.App, subpath string) {
 	api := app.Group(subpath)
+	read := handler.RequireScope(handler.ScopeFilesRead)
+	write := handler.RequireScope(handler.ScopeFilesWrite)
 
-	api.Get("/:id", handler.NewWithResult(h.l, h.v, "Get", handler.ParamsInput, h.service.Get).Handler())
-	api.Patch("/:id/move", handler.NewWithoutResult(h.l, h.v, "Move", handler.ParamAndQueryInput, h.service.Move).Handler())
-	api.Post("/", handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
-	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Delete", handler.ParamsInput, h.service.Delete).Handler())
-	api.Patch("/:id/rename", handler.NewWithoutResult(h.l, h.v, "Rename", handler.ParamAndQueryInput, h.service.Rename).Handler())
-	api.Put("/:id/update", handler.NewWithResult(h.l, h.v, "Update", handler.ParamAndQueryInput, h.service.Update).Handler())
-	api.Patch("/:id/star", handler.NewWithoutResult(h.l, h.v, "Star", handler.ParamsInput, h.service.Star).Handler())
-	api.Patch("/:id/share", handler.NewWithoutResult(h.l, h.v, "Publicate", handler.ParamAndQueryInput, h.service.Publicate).Handler())
+	api.Get("/duplicates", read, h.limiter.Middleware(ratelimit.SearchGroup), handler.NewWithResult(h.l, h.v, "Duplicates", handler.QueryInput, h.service.Duplicates).Handler())
+	api.Get("/usage", read, handler.NewWithResult(h.l, h.v, "Usage", handler.NoInput, h.service.Usage).Handler())
+	api.Get("/:id", read, handler.NewWithResult(h.l, h.v, "Get", handler.WithHeaders(handler.ParamsInput), h.service.Get).Handler())
+	api.Patch("/:id/move", write, handler.NewWithoutResult(h.l, h.v, "Move", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Move).Handler())
+	api.Post("/", write, h.limiter.Middleware(ratelimit.UploadGroup), handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
+	api.Delete("/:id", write, handler.NewWithoutResult(h.l, h.v, "Delete", handler.WithHeaders(handler.ParamsInput), h.service.Delete).Handler())
+	api.Patch("/:id/rename", write, handler.NewWithoutResult(h.l, h.v, "Rename", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Rename).Handler())
+	api.Put("/:id/update", write, h.limiter.Middleware(ratelimit.UploadGroup), handler.NewWithResult(h.l, h.v, "Update", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Update).Handler())
+	api.Patch("/:id/star", write, handler.NewWithoutResult(h.l, h.v, "Star", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Star).Handler())
+	api.Put("/:id/tags/:tagID", write, handler.NewWithoutResult(h.l, h.v, "AddTag", handler.WithHeaders(handler.ParamsInput), h.service.AddTag).Handler())
+	api.Delete("/:id/tags/:tagID", write, handler.NewWithoutResult(h.l, h.v, "RemoveTag", handler.WithHeaders(handler.ParamsInput), h.service.RemoveTag).Handler())
+	api.Patch("/:id/share", write, handler.NewWithoutResult(h.l, h.v, "Publicate", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Publicate).Handler())
+	api.Put("/:id/lock", write, handler.NewWithResult(h.l, h.v, "Lock", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Lock).Handler())
+	api.Delete("/:id/lock", write, handler.NewWithoutResult(h.l, h.v, "Unlock", handler.WithHeaders(handler.ParamAndQueryInput), h.service.Unlock).Handler())
+	api.Patch("/:id/attrs", write, handler.NewWithResult(h.l, h.v, "UpdateAttrs", handler.WithHeaders(handler.ParamAndBodyInput), h.service.UpdateAttrs).Handler())
 }
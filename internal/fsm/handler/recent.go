@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/recent"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type RecentService interface {
+	List(ctx owncontext.Context, data *recent.ListRequest) (*[]core.RecentFile, error)
+}
+
+type RecentHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service RecentService
+}
+
+func NewRecentHandler(l *slog.Logger, v *validator.Validate, recentService RecentService) *RecentHandler {
+	return &RecentHandler{
+		l:       l.With("module", "internal.fsm.handler.RecentHandler"),
+		v:       v,
+		service: recentService,
+	}
+}
+
+func (h *RecentHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.QueryInput, h.service.List).Handler())
+}
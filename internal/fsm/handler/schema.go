@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/schema"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type SchemaService interface {
+	List(ctx owncontext.Context, data *schema.ListRequest) (*[]core.AttrSchema, error)
+	Put(ctx owncontext.Context, data *schema.PutRequest) (*core.AttrSchema, error)
+	Delete(ctx owncontext.Context, data *schema.DeleteRequest) error
+}
+
+type SchemaHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service SchemaService
+}
+
+func NewSchemaHandler(l *slog.Logger, v *validator.Validate, schemaService SchemaService) *SchemaHandler {
+	return &SchemaHandler{
+		l:       l.With("module", "internal.fsm.handler.SchemaHandler"),
+		v:       v,
+		service: schemaService,
+	}
+}
+
+func (h *SchemaHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.NoInput, h.service.List).Handler())
+	api.Put("/", handler.NewWithResult(h.l, h.v, "Put", handler.BodyInput, h.service.Put).Handler())
+	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Delete", handler.ParamsInput, h.service.Delete).Handler())
+}
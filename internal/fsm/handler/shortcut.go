@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/shortcut"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type ShortcutService interface {
+	Create(ctx owncontext.Context, data *shortcut.CreateRequest) (*core.Shortcut, error)
+	Rename(ctx owncontext.Context, data *shortcut.RenameRequest) error
+	Delete(ctx owncontext.Context, data *shortcut.DeleteRequest) error
+}
+
+type ShortcutHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service ShortcutService
+}
+
+func NewShortcutHandler(l *slog.Logger, v *validator.Validate, shortcutService ShortcutService) *ShortcutHandler {
+	return &ShortcutHandler{
+		l:       l.With("module", "internal.fsm.handler.ShortcutHandler"),
+		v:       v,
+		service: shortcutService,
+	}
+}
+
+func (h *ShortcutHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Post("/", handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
+	api.Patch("/:id/rename", handler.NewWithoutResult(h.l, h.v, "Rename", handler.ParamAndQueryInput, h.service.Rename).Handler())
+	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Delete", handler.ParamsInput, h.service.Delete).Handler())
+}
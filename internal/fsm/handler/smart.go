@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/ratelimit"
+	"github.com/StratuStore/fsm/internal/fsm/service/smart"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type SmartService interface {
+	List(ctx owncontext.Context, data *smart.ListRequest) (*[]core.SavedSearch, error)
+	Create(ctx owncontext.Context, data *smart.CreateRequest) (*core.SavedSearch, error)
+	Update(ctx owncontext.Context, data *smart.UpdateRequest) (*core.SavedSearch, error)
+	Delete(ctx owncontext.Context, data *smart.DeleteRequest) error
+	Execute(ctx owncontext.Context, data *smart.ExecuteRequest) (*core.DirectoryLike, error)
+}
+
+type SmartHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service SmartService
+	limiter *ratelimit.Limiter
+}
+
+func NewSmartHandler(l *slog.Logger, v *validator.Validate, smartService SmartService, limiter *ratelimit.Limiter) *SmartHandler {
+	return &SmartHandler{
+		l:       l.With("module", "internal.fsm.handler.SmartHandler"),
+		v:       v,
+		service: smartService,
+		limiter: limiter,
+	}
+}
+
+func (h *SmartHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.NoInput, h.service.List).Handler())
+	api.Get("/:id", h.limiter.Middleware(ratelimit.SearchGroup), handler.NewWithResult(h.l, h.v, "Execute", handler.ParamAndQueryInput, h.service.Execute).Handler())
+	api.Post("/", handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
+	api.Patch("/:id", handler.NewWithResult(h.l, h.v, "Update", handler.ParamAndBodyInput, h.service.Update).Handler())
+	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Delete", handler.ParamsInput, h.service.Delete).Handler())
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/directory"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type StarredService interface {
+	Starred(ctx owncontext.Context, data *directory.StarredRequest) (*core.DirectoryLike, error)
+}
+
+type StarredHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service StarredService
+}
+
+func NewStarredHandler(l *slog.Logger, v *validator.Validate, starredService StarredService) *StarredHandler {
+	return &StarredHandler{
+		l:       l.With("module", "internal.fsm.handler.StarredHandler"),
+		v:       v,
+		service: starredService,
+	}
+}
+
+func (h *StarredHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "Starred", handler.QueryInput, h.service.Starred).Handler())
+}
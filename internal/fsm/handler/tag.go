@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/tag"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type TagService interface {
+	List(ctx owncontext.Context, data *tag.ListRequest) (*[]core.Tag, error)
+	Create(ctx owncontext.Context, data *tag.CreateRequest) (*core.Tag, error)
+	Update(ctx owncontext.Context, data *tag.UpdateRequest) (*core.Tag, error)
+	Delete(ctx owncontext.Context, data *tag.DeleteRequest) error
+}
+
+type TagHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service TagService
+}
+
+func NewTagHandler(l *slog.Logger, v *validator.Validate, tagService TagService) *TagHandler {
+	return &TagHandler{
+		l:       l.With("module", "internal.fsm.handler.TagHandler"),
+		v:       v,
+		service: tagService,
+	}
+}
+
+func (h *TagHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.NoInput, h.service.List).Handler())
+	api.Post("/", handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
+	api.Patch("/:id", handler.NewWithResult(h.l, h.v, "Update", handler.ParamAndBodyInput, h.service.Update).Handler())
+	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Delete", handler.ParamsInput, h.service.Delete).Handler())
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/tenant"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type TenantService interface {
+	Current(ctx owncontext.Context, data *tenant.CurrentRequest) (*core.Tenant, error)
+	List(ctx owncontext.Context, data *tenant.ListRequest) (*[]core.Tenant, error)
+	Get(ctx owncontext.Context, data *tenant.GetRequest) (*core.Tenant, error)
+	Put(ctx owncontext.Context, data *tenant.PutRequest) (*core.Tenant, error)
+	Delete(ctx owncontext.Context, data *tenant.DeleteRequest) error
+}
+
+type TenantHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service TenantService
+}
+
+func NewTenantHandler(l *slog.Logger, v *validator.Validate, tenantService TenantService) *TenantHandler {
+	return &TenantHandler{
+		l:       l.With("module", "internal.fsm.handler.TenantHandler"),
+		v:       v,
+		service: tenantService,
+	}
+}
+
+func (h *TenantHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/current", handler.NewWithResult(h.l, h.v, "Current", handler.NoInput, h.service.Current).Handler())
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.NoInput, h.service.List).Handler())
+	api.Get("/:id", handler.NewWithResult(h.l, h.v, "Get", handler.ParamsInput, h.service.Get).Handler())
+	api.Put("/:id", handler.NewWithResult(h.l, h.v, "Put", handler.ParamAndBodyInput, h.service.Put).Handler())
+	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Delete", handler.ParamsInput, h.service.Delete).Handler())
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/token"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type TokenService interface {
+	Create(ctx owncontext.Context, data *token.CreateRequest) (*token.CreateResponse, error)
+	List(ctx owncontext.Context, data *token.ListRequest) (*[]core.APIToken, error)
+	Revoke(ctx owncontext.Context, data *token.RevokeRequest) error
+}
+
+type TokenHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service TokenService
+}
+
+func NewTokenHandler(l *slog.Logger, v *validator.Validate, tokenService TokenService) *TokenHandler {
+	return &TokenHandler{
+		l:       l.With("module", "internal.fsm.handler.TokenHandler"),
+		v:       v,
+		service: tokenService,
+	}
+}
+
+func (h *TokenHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.NoInput, h.service.List).Handler())
+	api.Post("/", handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
+	api.Delete("/:id", handler.NewWithoutResult(h.l, h.v, "Revoke", handler.ParamsInput, h.service.Revoke).Handler())
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/workspace"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+)
+
+type WorkspaceService interface {
+	List(ctx owncontext.Context, data *workspace.ListRequest) (*[]core.Workspace, error)
+	Get(ctx owncontext.Context, data *workspace.GetRequest) (*core.Workspace, error)
+	Create(ctx owncontext.Context, data *workspace.CreateRequest) (*core.Workspace, error)
+	Update(ctx owncontext.Context, data *workspace.UpdateRequest) (*core.Workspace, error)
+	SetMember(ctx owncontext.Context, data *workspace.SetMemberRequest) error
+	RemoveMember(ctx owncontext.Context, data *workspace.RemoveMemberRequest) error
+}
+
+type WorkspaceHandler struct {
+	l       *slog.Logger
+	v       *validator.Validate
+	service WorkspaceService
+}
+
+func NewWorkspaceHandler(l *slog.Logger, v *validator.Validate, workspaceService WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		l:       l.With("module", "internal.fsm.handler.WorkspaceHandler"),
+		v:       v,
+		service: workspaceService,
+	}
+}
+
+func (h *WorkspaceHandler) Register(app *fiber.App, subpath string) {
+	api := app.Group(subpath)
+
+	api.Get("/", handler.NewWithResult(h.l, h.v, "List", handler.NoInput, h.service.List).Handler())
+	api.Post("/", handler.NewWithResult(h.l, h.v, "Create", handler.BodyInput, h.service.Create).Handler())
+	api.Get("/:id", handler.NewWithResult(h.l, h.v, "Get", handler.ParamsInput, h.service.Get).Handler())
+	api.Patch("/:id", handler.NewWithResult(h.l, h.v, "Update", handler.ParamAndBodyInput, h.service.Update).Handler())
+	api.Put("/:id/members/:userID", handler.NewWithoutResult(h.l, h.v, "SetMember", handler.ParamAndBodyInput, h.service.SetMember).Handler())
+	api.Delete("/:id/members/:userID", handler.NewWithoutResult(h.l, h.v, "RemoveMember", handler.ParamsInput, h.service.RemoveMember).Handler())
+}
@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	MongoCheck  = "mongodb"
+	BrokerCheck = "broker"
+	FSCheck     = "fs"
+)
+
+type Status string
+
+const (
+	StatusUp Status = "up"
+	// StatusDegraded means only optional dependencies failed, the service is still ready
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+type Storage interface {
+	Ping(ctx context.Context) error
+}
+
+type Communicator interface {
+	CheckConnection() error
+	Ping(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Status    Status  `json:"status"`
+	Optional  bool    `json:"optional"`
+	LatencyMS float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type check struct {
+	name     string
+	optional bool
+	f        func(ctx context.Context) error
+}
+
+// Health checks the dependencies of the service. Failing required checks make it down,
+// failing optional ones, see config.Health, degraded.
+type Health struct {
+	l       *slog.Logger
+	timeout time.Duration
+	checks  []check
+}
+
+func New(l *slog.Logger, cfg *config.Config, s Storage, c Communicator) *Health {
+	h := &Health{
+		l:       l.With("module", "internal.fsm.health.Health"),
+		timeout: cfg.HealthTimeout,
+	}
+
+	h.add(cfg, MongoCheck, s.Ping)
+	h.add(cfg, BrokerCheck, func(context.Context) error {
+		return c.CheckConnection()
+	})
+	if cfg.HealthFSPing {
+		h.add(cfg, FSCheck, c.Ping)
+	}
+
+	return h
+}
+
+func (h *Health) add(cfg *config.Config, name string, f func(ctx context.Context) error) {
+	h.checks = append(h.checks, check{
+		name:     name,
+		optional: slices.Contains(cfg.HealthOptional, name),
+		f:        f,
+	})
+}
+
+// Report runs all checks concurrently, each one limited by the configured timeout.
+func (h *Health) Report(ctx context.Context) *Report {
+	l := h.l.With(slog.String("op", "Report"))
+
+	results := make([]CheckResult, len(h.checks))
+
+	var wg sync.WaitGroup
+	for num, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[num] = h.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(h.checks))}
+	for num, check := range h.checks {
+		result := results[num]
+		report.Checks[check.name] = result
+		if result.Status == StatusUp {
+			continue
+		}
+
+		l.Warn("health check failed", slog.String("check", check.name), slog.String("err", result.Error))
+		if !check.optional {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (h *Health) run(ctx context.Context, check check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.f(ctx)
+	result := CheckResult{
+		Status:    StatusUp,
+		Optional:  check.optional,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Ready reports whether the service can serve requests, it is if no required check fails.
+func (h *Health) Ready(ctx context.Context) bool {
+	return h.Report(ctx).Status != StatusDown
+}
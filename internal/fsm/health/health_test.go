@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/stretchr/testify/require"
+)
+
+type storage struct {
+	err error
+}
+
+func (s storage) Ping(context.Context) error {
+	return s.err
+}
+
+type communicator struct {
+	connection error
+	// hangs makes Ping wait for the timeout
+	hangs bool
+}
+
+func (c communicator) CheckConnection() error {
+	return c.connection
+}
+
+func (c communicator) Ping(ctx context.Context) error {
+	if c.hangs {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return c.connection
+}
+
+func TestReport(t *testing.T) {
+	failure := errors.New("failure")
+	cases := []struct {
+		name   string
+		s      storage
+		c      communicator
+		status Status
+		failed []string
+	}{
+		{"up", storage{}, communicator{}, StatusUp, nil},
+		{"fs times out", storage{}, communicator{hangs: true}, StatusDegraded, []string{FSCheck}},
+		{"broker down", storage{}, communicator{connection: failure}, StatusDown, []string{BrokerCheck, FSCheck}},
+		{"mongo down", storage{err: failure}, communicator{}, StatusDown, []string{MongoCheck}},
+	}
+
+	cfg := &config.Config{Health: config.Health{
+		HealthTimeout:  10 * time.Millisecond,
+		HealthFSPing:   true,
+		HealthOptional: []string{FSCheck},
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := New(slog.New(slog.DiscardHandler), cfg, c.s, c.c)
+
+			report := h.Report(context.Background())
+
+			require.Equal(t, c.status, report.Status)
+			require.Len(t, report.Checks, 3)
+			for name, result := range report.Checks {
+				require.Equal(t, name == FSCheck, result.Optional)
+				if slices.Contains(c.failed, name) {
+					require.Equal(t, StatusDown, result.Status, name)
+					require.NotEmpty(t, result.Error, name)
+				} else {
+					require.Equal(t, StatusUp, result.Status, name)
+				}
+			}
+			require.Equal(t, c.status != StatusDown, h.Ready(context.Background()))
+		})
+	}
+}
+
+func TestReportWithoutFSPing(t *testing.T) {
+	cfg := &config.Config{Health: config.Health{HealthTimeout: time.Second}}
+	h := New(slog.New(slog.DiscardHandler), cfg, storage{}, communicator{hangs: true})
+
+	report := h.Report(context.Background())
+
+	require.Equal(t, StatusUp, report.Status)
+	require.NotContains(t, report.Checks, FSCheck)
+}
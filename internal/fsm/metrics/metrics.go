@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"strconv"
+	"time"
+)
+
+const namespace = "fsm"
+
+// Metrics are the Prometheus metrics of the service, they are served by Handler.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	storageDuration *prometheus.HistogramVec
+	fsDuration      *prometheus.HistogramVec
+	fsTimeouts      *prometheus.CounterVec
+	fsPending       *prometheus.GaugeVec
+	created         *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by handler, route, method and status.",
+		}, []string{"handler", "route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests by handler, route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"handler", "route", "method"}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "storage",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of MongoDB operations by storage method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		fsDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "fs",
+			Name:      "round_trip_seconds",
+			Help:      "Time from publishing a request to the FS until its callback, by request type.",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"type"}),
+		fsTimeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "fs",
+			Name:      "timeouts_total",
+			Help:      "Requests to the FS given up before the callback arrived, by request type.",
+		}, []string{"type"}),
+		fsPending: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "fs",
+			Name:      "pending_requests",
+			Help:      "Requests to the FS waiting for their callback, by request type.",
+		}, []string{"type"}),
+		created: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "created_total",
+			Help:      "Files and directories created, by kind.",
+		}, []string{"kind"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.storageDuration,
+		m.fsDuration,
+		m.fsTimeouts,
+		m.fsPending,
+		m.created,
+	)
+
+	return m
+}
+
+// Register adds further collectors, e.g. NewTotalsCollector.
+func (m *Metrics) Register(c prometheus.Collector) error {
+	return m.registry.Register(c)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware counts and times every request. The handler label is the name given to handler.Handler,
+// empty for routes not served by it.
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// the error handler sets the final status only after the middlewares returned
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+
+		name, _ := c.Locals(handler.NameLocal).(string)
+		route := c.Route().Path
+		m.requests.WithLabelValues(name, route, c.Method(), strconv.Itoa(status)).Inc()
+		m.requestDuration.WithLabelValues(name, route, c.Method()).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// ObserveStorage starts timing a storage method, the returned function stops it.
+func (m *Metrics) ObserveStorage(method string) func() {
+	start := time.Now()
+
+	return func() {
+		m.storageDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveFS marks a request to the FS as pending, the returned function is called with the result of waiting
+// for its callback. Requests given up because of their deadline are counted as timeouts instead of timed.
+func (m *Metrics) ObserveFS(requestType string) func(err error) {
+	start := time.Now()
+	m.fsPending.WithLabelValues(requestType).Inc()
+
+	return func(err error) {
+		m.fsPending.WithLabelValues(requestType).Dec()
+
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			m.fsTimeouts.WithLabelValues(requestType).Inc()
+		case err == nil:
+			m.fsDuration.WithLabelValues(requestType).Observe(time.Since(start).Seconds())
+		}
+	}
+}
+
+// Created counts a created file or directory.
+func (m *Metrics) Created(kind string) {
+	m.created.WithLabelValues(kind).Inc()
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareLabelsHandler(t *testing.T) {
+	m := New()
+	app := fiber.New()
+	app.Use(m.Middleware())
+	app.Get("/file/:id", func(c *fiber.Ctx) error {
+		c.Locals(handler.NameLocal, "Get")
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	for range 2 {
+		_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/file/1", nil))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues("Get", "/file/:id", fiber.MethodGet, "201")))
+}
+
+func TestObserveFS(t *testing.T) {
+	m := New()
+
+	done := m.ObserveFS("open")
+	require.Equal(t, 1.0, testutil.ToFloat64(m.fsPending.WithLabelValues("open")))
+	done(nil)
+
+	m.ObserveFS("open")(context.DeadlineExceeded)
+	m.ObserveFS("open")(errors.New("other"))
+
+	require.Equal(t, 0.0, testutil.ToFloat64(m.fsPending.WithLabelValues("open")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.fsTimeouts.WithLabelValues("open")))
+	require.Equal(t, 1, testutil.CollectAndCount(m.fsDuration))
+}
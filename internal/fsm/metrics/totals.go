@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/prometheus/client_golang/prometheus"
+	"log/slog"
+	"time"
+)
+
+const totalsTimeout = 5 * time.Second
+
+type TotalsSource interface {
+	Totals(ctx context.Context) (*core.Totals, error)
+}
+
+// TotalsCollector reports what is stored across all users, it is read from the database on every scrape.
+type TotalsCollector struct {
+	l           *slog.Logger
+	s           TotalsSource
+	files       *prometheus.Desc
+	directories *prometheus.Desc
+	bytes       *prometheus.Desc
+}
+
+func NewTotalsCollector(l *slog.Logger, s TotalsSource) *TotalsCollector {
+	return &TotalsCollector{
+		l:           l.With("module", "internal.fsm.metrics.TotalsCollector"),
+		s:           s,
+		files:       prometheus.NewDesc(namespace+"_stored_files", "Files stored.", nil, nil),
+		directories: prometheus.NewDesc(namespace+"_stored_directories", "Directories stored.", nil, nil),
+		bytes:       prometheus.NewDesc(namespace+"_stored_bytes", "Bytes stored.", nil, nil),
+	}
+}
+
+func (c *TotalsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.files
+	ch <- c.directories
+	ch <- c.bytes
+}
+
+func (c *TotalsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), totalsTimeout)
+	defer cancel()
+
+	totals, err := c.s.Totals(ctx)
+	if err != nil {
+		c.l.Error("unable to read totals", slog.String("err", err.Error()))
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.files, prometheus.GaugeValue, float64(totals.Files))
+	ch <- prometheus.MustNewConstMetric(c.directories, prometheus.GaugeValue, float64(totals.Directories))
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(totals.Bytes))
+}
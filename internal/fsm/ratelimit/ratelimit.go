@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/utils"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Route groups, every request counts against DefaultGroup, the expensive ones additionally against their group.
+const (
+	DefaultGroup = "default"
+	// SearchGroup are the routes running aggregations
+	SearchGroup = "search"
+	// UploadGroup are the routes allocating space on the FS
+	UploadGroup = "upload"
+)
+
+// Rate is a token bucket holding up to Burst tokens, refilled with Sustained tokens per second.
+type Rate struct {
+	Sustained float64
+	Burst     uint
+}
+
+// Refill is the time an empty bucket takes to be full again.
+func (r Rate) Refill() time.Duration {
+	return time.Duration(float64(r.Burst) / r.Sustained * float64(time.Second))
+}
+
+type Result struct {
+	Allowed bool
+	// RetryAfter is the time until the next token, if the request is not allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets, Take has to be atomic for a key.
+type Store interface {
+	Take(ctx context.Context, key string, rate Rate, now time.Time) (Result, error)
+}
+
+// Limiter limits the requests of each user per route group.
+type Limiter struct {
+	l     *slog.Logger
+	store Store
+	rates map[string]Rate
+}
+
+func New(l *slog.Logger, cfg *config.Config, store Store) *Limiter {
+	return &Limiter{
+		l:     l.With(slog.String("module", "internal.fsm.ratelimit.Limiter")),
+		store: store,
+		rates: map[string]Rate{
+			DefaultGroup: {Sustained: cfg.RateLimitRate, Burst: cfg.RateLimitBurst},
+			SearchGroup:  {Sustained: cfg.RateLimitSearchRate, Burst: cfg.RateLimitSearchBurst},
+			UploadGroup:  {Sustained: cfg.RateLimitUploadRate, Burst: cfg.RateLimitUploadBurst},
+		},
+	}
+}
+
+// Middleware responds with 429 and Retry-After if the user exceeds the rate of group.
+// It has to run after the JWT middleware, if the store fails the request is let through.
+func (r *Limiter) Middleware(group string) fiber.Handler {
+	rate := r.rates[group]
+	if rate.Sustained <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	if rate.Burst == 0 {
+		rate.Burst = 1
+	}
+
+	return func(c *fiber.Ctx) error {
+		l := r.l.With(slog.String("op", "Middleware"), slog.String("group", group), slog.String("request_id", handler.GetRequestID(c)))
+
+		userID, err := handler.GetUserID(l, c)
+		if err != nil {
+			userID = "ip:" + c.IP()
+		}
+
+		result, err := r.store.Take(c.UserContext(), group+":"+userID, rate, time.Now())
+		if err != nil {
+			l.Error("unable to take token", slog.String("err", err.Error()))
+
+			return c.Next()
+		}
+		if !result.Allowed {
+			l.Debug("rate limit exceeded", slog.String("user_id", userID))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+
+			return c.Status(fiber.StatusTooManyRequests).JSON(utils.NewErrorResponse("rate limit exceeded"))
+		}
+
+		return c.Next()
+	}
+}
+
+// take removes a token from a bucket holding tokens, last refilled at updatedAt, and returns the tokens left.
+func take(tokens float64, updatedAt time.Time, rate Rate, now time.Time) (float64, Result) {
+	elapsed := max(now.Sub(updatedAt).Seconds(), 0)
+	tokens = min(tokens+elapsed*rate.Sustained, float64(rate.Burst))
+
+	if tokens < 1 {
+		return tokens, Result{
+			RetryAfter: time.Duration((1 - tokens) / rate.Sustained * float64(time.Second)),
+		}
+	}
+
+	return tokens - 1, Result{Allowed: true}
+}
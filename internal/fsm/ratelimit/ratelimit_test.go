@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	s := NewMemoryStore()
+	rate := Rate{Sustained: 2, Burst: 3}
+	now := time.Now()
+
+	for range 3 {
+		result, err := s.Take(context.Background(), "user", rate, now)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	result, err := s.Take(context.Background(), "user", rate, now)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	result, _ = s.Take(context.Background(), "other", rate, now)
+	require.True(t, result.Allowed)
+
+	result, _ = s.Take(context.Background(), "user", rate, now.Add(500*time.Millisecond))
+	require.True(t, result.Allowed)
+
+	// buckets are dropped once full, a later request still gets the whole burst
+	s.Take(context.Background(), "user", rate, now.Add(time.Hour))
+	require.Len(t, s.buckets, 1)
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := &config.Config{RateLimit: config.RateLimit{RateLimitSearchRate: 0.5, RateLimitSearchBurst: 1}}
+	limiter := New(slog.Default(), cfg, NewMemoryStore())
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"id": c.Get("X-User")}})
+		return c.Next()
+	})
+	app.Get("/search", limiter.Middleware(SearchGroup), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/other", limiter.Middleware(DefaultGroup), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(path, userID string) *http.Response {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set("X-User", userID)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp
+	}
+
+	require.Equal(t, fiber.StatusOK, request("/search", "a").StatusCode)
+
+	limited := request("/search", "a")
+	require.Equal(t, fiber.StatusTooManyRequests, limited.StatusCode)
+	require.Equal(t, "2", limited.Header.Get(fiber.HeaderRetryAfter))
+
+	require.Equal(t, fiber.StatusOK, request("/search", "b").StatusCode)
+	// a rate of 0 disables the limit
+	for range 3 {
+		require.Equal(t, fiber.StatusOK, request("/other", "a").StatusCode)
+	}
+}
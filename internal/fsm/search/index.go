@@ -0,0 +1,63 @@
+package search
+
+import (
+	"regexp"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// KeywordsField is the document field holding the tokens produced by Index.Keywords.
+const KeywordsField = "keywords"
+
+// Index decides how entries are tokenized when stored and how free-text queries are matched against them.
+type Index interface {
+	// Keywords returns the tokens stored alongside an entry with the given name and attributes.
+	Keywords(name string, attrs map[string]string) []string
+	// Match returns the filter selecting entries matching query and an aggregation
+	// expression scoring them, the higher the more relevant.
+	// Both are nil when query contains no tokens.
+	Match(query string) (filter bson.D, score any)
+}
+
+// KeywordIndex is an Index backed by a multikey MongoDB index over KeywordsField.
+// Every query token must be a prefix of some keyword, exact keyword hits rank higher.
+type KeywordIndex struct{}
+
+func NewKeywordIndex() *KeywordIndex {
+	return &KeywordIndex{}
+}
+
+func (i *KeywordIndex) Keywords(name string, attrs map[string]string) []string {
+	keywords := Tokenize(name)
+	for _, value := range attrs {
+		keywords = append(keywords, Tokenize(value)...)
+	}
+
+	slices.Sort(keywords)
+
+	return slices.Compact(keywords)
+}
+
+func (i *KeywordIndex) Match(query string) (bson.D, any) {
+	tokens := Tokenize(query)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	slices.Sort(tokens)
+	tokens = slices.Compact(tokens)
+
+	prefixes := make([]primitive.Regex, len(tokens))
+	score := make([]bson.M, len(tokens))
+	for num, token := range tokens {
+		prefixes[num] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(token)}
+		score[num] = bson.M{"$cond": []any{
+			bson.M{"$in": []any{token, bson.M{"$ifNull": []any{"$" + KeywordsField, []string{}}}}},
+			2,
+			1,
+		}}
+	}
+
+	return bson.D{{KeywordsField, bson.D{{"$all", prefixes}}}}, bson.M{"$add": score}
+}
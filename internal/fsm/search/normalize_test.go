@@ -0,0 +1,50 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Report", []string{"report"}},
+		{"Résumé_2024.final", []string{"resume", "2024", "final"}},
+		{"  Ångström  Über ", []string{"angstrom", "uber"}},
+		{"---", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			require.Equal(t, tt.want, Tokenize(tt.in))
+		})
+	}
+}
+
+func TestKeywordIndexKeywords(t *testing.T) {
+	keywords := NewKeywordIndex().Keywords("Quarterly Report", map[string]string{
+		"author": "José",
+		"topic":  "report",
+	})
+
+	require.Equal(t, []string{"jose", "quarterly", "report"}, keywords)
+}
+
+func TestKeywordIndexMatch(t *testing.T) {
+	filter, score := NewKeywordIndex().Match("Rép rep Quart")
+
+	require.Equal(t, bson.D{{KeywordsField, bson.D{{"$all", []primitive.Regex{
+		{Pattern: "^quart"},
+		{Pattern: "^rep"},
+	}}}}}, filter)
+	require.Len(t, score.(bson.M)["$add"], 2)
+
+	filter, score = NewKeywordIndex().Match(" -- ")
+	require.Nil(t, filter)
+	require.Nil(t, score)
+}
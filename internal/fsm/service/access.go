@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"net/http"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Access decides what the user of a request may do with items of an owner,
+// which is either a user ID or a workspace, see core.WorkspaceOwner.
+type Access interface {
+	CanRead(ctx owncontext.Context, owner string) (bool, error)
+	CanWrite(ctx owncontext.Context, owner string) (bool, error)
+	// InSubtree reports whether the directory is inside the subtree the token of the request is limited to,
+	// see owncontext.Context.Subtree.
+	InSubtree(ctx owncontext.Context, directoryID types.ObjectId) (bool, error)
+	// CheckQuota returns ErrQuotaExceeded if the owner can not store size more bytes.
+	CheckQuota(ctx context.Context, owner string, size int) error
+	// Tenant returns the configuration of the tenant of the user.
+	Tenant(ctx owncontext.Context) (*core.Tenant, error)
+}
+
+// CheckRead returns an error unless the user can read items of the owner. Public items are readable by everyone.
+func CheckRead(l *slog.Logger, a Access, ctx owncontext.Context, owner string, public bool) error {
+	if public {
+		return nil
+	}
+
+	ok, err := a.CanRead(ctx, owner)
+	if err != nil {
+		return NewDBError(l, err)
+	}
+	if !ok {
+		return NewWrongUserError(l)
+	}
+
+	return nil
+}
+
+// CheckWrite returns an error unless the user can change items of the owner.
+func CheckWrite(l *slog.Logger, a Access, ctx owncontext.Context, owner string) error {
+	ok, err := a.CanWrite(ctx, owner)
+	if err != nil {
+		return NewDBError(l, err)
+	}
+	if !ok {
+		return NewWrongUserError(l)
+	}
+
+	return nil
+}
+
+// CheckSubtree returns an error if the token of the request is limited to a subtree not containing the directory.
+// Items of files are checked by their parent directory.
+func CheckSubtree(l *slog.Logger, a Access, ctx owncontext.Context, directoryID types.ObjectId) error {
+	if ctx.Subtree() == "" {
+		return nil
+	}
+
+	ok, err := a.InSubtree(ctx, directoryID)
+	if err != nil {
+		return NewDBError(l, err)
+	}
+	if !ok {
+		return ownerrors.NewForbiddenError(l, "outside of the subtree of the token", "the token is limited to another directory")
+	}
+
+	return nil
+}
+
+// CheckUnrestricted returns an error if the token of the request is limited to a subtree,
+// for operations on every item of the user.
+func CheckUnrestricted(l *slog.Logger, ctx owncontext.Context) error {
+	if ctx.Subtree() != "" {
+		return ownerrors.NewForbiddenError(l, "token limited to a subtree", "the token is limited to a directory")
+	}
+
+	return nil
+}
+
+// CheckQuota returns an error if the owner can not store size more bytes.
+func CheckQuota(l *slog.Logger, a Access, ctx context.Context, owner string, size int) error {
+	err := a.CheckQuota(ctx, owner, size)
+	if errors.Is(err, ErrQuotaExceeded) {
+		return ownerrors.NewError(l, http.StatusInsufficientStorage, "quota exceeded", "quota exceeded", err)
+	}
+
+	return NewDBError(l, err)
+}
+
+// CheckPublicSharing returns an error if the tenant of the user does not allow public items.
+func CheckPublicSharing(l *slog.Logger, a Access, ctx owncontext.Context) error {
+	tenant, err := a.Tenant(ctx)
+	if err != nil {
+		return NewDBError(l, err)
+	}
+	if !tenant.PublicSharing {
+		return ownerrors.NewForbiddenError(l, "public sharing disabled", "public sharing is disabled for the organization")
+	}
+
+	return nil
+}
+
+// CheckWorkspaces returns an error if the tenant of the user does not allow shared workspaces.
+func CheckWorkspaces(l *slog.Logger, a Access, ctx owncontext.Context) error {
+	tenant, err := a.Tenant(ctx)
+	if err != nil {
+		return NewDBError(l, err)
+	}
+	if !tenant.Workspaces {
+		return ownerrors.NewForbiddenError(l, "workspaces disabled", "workspaces are disabled for the organization")
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type Storage interface {
+	GetWorkspace(ctx context.Context, id types.ObjectId) (*core.Workspace, error)
+	GetDirectory(ctx context.Context, id types.ObjectId) (*core.Directory, error)
+	GetTenant(ctx context.Context, id string) (*core.Tenant, error)
+	GetRootSize(ctx context.Context, owner string) (uint, error)
+}
+
+// Access implements service.Access. Users have full access to their own items,
+// items of workspaces are accessible according to the role of the user in the workspace.
+type Access struct {
+	l *slog.Logger
+	s Storage
+}
+
+func New(l *slog.Logger, s Storage) *Access {
+	return &Access{
+		l: l.With("module", "internal.fsm.service.access.Access"),
+		s: s,
+	}
+}
+
+func (a *Access) CanRead(ctx owncontext.Context, owner string) (bool, error) {
+	if owner == ctx.UserID() {
+		return true, nil
+	}
+
+	role, err := a.role(ctx, owner)
+	if err != nil {
+		return false, err
+	}
+
+	return role != "", nil
+}
+
+func (a *Access) CanWrite(ctx owncontext.Context, owner string) (bool, error) {
+	if owner == ctx.UserID() {
+		return true, nil
+	}
+
+	role, err := a.role(ctx, owner)
+	if err != nil {
+		return false, err
+	}
+
+	return role.CanWrite(), nil
+}
+
+// InSubtree reports whether the directory is inside the subtree the token of the request is limited to.
+func (a *Access) InSubtree(ctx owncontext.Context, directoryID types.ObjectId) (bool, error) {
+	root := types.ObjectId(ctx.Subtree())
+	if root.IsZero() || directoryID == root {
+		return true, nil
+	}
+
+	dir, err := a.s.GetDirectory(ctx, directoryID)
+	if err != nil {
+		return false, fmt.Errorf("unable to get directory: %w", err)
+	}
+
+	return dir.Within(root), nil
+}
+
+func (a *Access) Tenant(ctx owncontext.Context) (*core.Tenant, error) {
+	tenant, err := a.s.GetTenant(ctx, ctx.TenantID())
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tenant: %w", err)
+	}
+
+	return tenant, nil
+}
+
+// CheckQuota compares the size of the workspace root with the quota of the workspace,
+// the size of the root of a user with the user quota of the tenant.
+func (a *Access) CheckQuota(ctx context.Context, owner string, size int) error {
+	if size <= 0 {
+		return nil
+	}
+
+	id, ok := core.ParseWorkspaceOwner(owner)
+	if !ok {
+		return a.checkUserQuota(ctx, owner, size)
+	}
+
+	workspace, err := a.s.GetWorkspace(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to get workspace: %w", err)
+	}
+	if workspace.Quota == 0 {
+		return nil
+	}
+
+	root, err := a.s.GetDirectory(ctx, workspace.RootID)
+	if err != nil {
+		return fmt.Errorf("unable to get workspace root: %w", err)
+	}
+	if int(root.Size)+size > int(workspace.Quota) {
+		return service.ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+func (a *Access) checkUserQuota(ctx context.Context, owner string, size int) error {
+	tenantID, ok := owncontext.TenantID(ctx)
+	if !ok {
+		return nil
+	}
+
+	tenant, err := a.s.GetTenant(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("unable to get tenant: %w", err)
+	}
+	if tenant.UserQuota == 0 {
+		return nil
+	}
+
+	used, err := a.s.GetRootSize(ctx, owner)
+	if err != nil {
+		return fmt.Errorf("unable to get root size: %w", err)
+	}
+	if int(used)+size > int(tenant.UserQuota) {
+		return service.ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+// role returns the role of the user in the workspace owning the items, empty if there is none.
+func (a *Access) role(ctx owncontext.Context, owner string) (core.Role, error) {
+	id, ok := core.ParseWorkspaceOwner(owner)
+	if !ok {
+		return "", nil
+	}
+
+	workspace, err := a.s.GetWorkspace(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("unable to get workspace: %w", err)
+	}
+
+	role, _ := workspace.Role(ctx.UserID())
+
+	return role, nil
+}
@@ -0,0 +1,119 @@
+package access
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	workspaceID = types.ObjectId("65f000000000000000000001")
+	rootID      = types.ObjectId("65f000000000000000000002")
+	nestedID    = types.ObjectId("65f000000000000000000003")
+)
+
+type storage struct{}
+
+func (storage) GetWorkspace(_ context.Context, id types.ObjectId) (*core.Workspace, error) {
+	return &core.Workspace{
+		ID:     id,
+		RootID: rootID,
+		Quota:  100,
+		Members: []core.Member{
+			{UserID: "owner", Role: core.RoleOwner},
+			{UserID: "editor", Role: core.RoleEditor},
+			{UserID: "viewer", Role: core.RoleViewer},
+		},
+	}, nil
+}
+
+func (storage) GetDirectory(_ context.Context, id types.ObjectId) (*core.Directory, error) {
+	dir := &core.Directory{ID: id, Size: 60}
+	if id == nestedID {
+		dir.Path = []core.PathElement{{ID: rootID}}
+	}
+
+	return dir, nil
+}
+
+func (storage) GetTenant(_ context.Context, id string) (*core.Tenant, error) {
+	tenant := core.DefaultTenant(id)
+	if id == "limited" {
+		tenant.UserQuota = 100
+	}
+
+	return tenant, nil
+}
+
+func (storage) GetRootSize(_ context.Context, _ string) (uint, error) {
+	return 90, nil
+}
+
+func TestAccessRoles(t *testing.T) {
+	a := New(slog.New(slog.DiscardHandler), storage{})
+	owner := core.WorkspaceOwner(workspaceID)
+
+	for _, c := range []struct {
+		userID, owner     string
+		canRead, canWrite bool
+	}{
+		{"user", "user", true, true},
+		{"user", "other", false, false},
+		{"owner", owner, true, true},
+		{"editor", owner, true, true},
+		{"viewer", owner, true, false},
+		{"user", owner, false, false},
+	} {
+		ctx := owncontext.New(context.Background(), c.userID)
+
+		canRead, err := a.CanRead(ctx, c.owner)
+		require.NoError(t, err)
+		require.Equal(t, c.canRead, canRead, "%v reads %v", c.userID, c.owner)
+
+		canWrite, err := a.CanWrite(ctx, c.owner)
+		require.NoError(t, err)
+		require.Equal(t, c.canWrite, canWrite, "%v writes %v", c.userID, c.owner)
+	}
+}
+
+func TestAccessQuota(t *testing.T) {
+	a := New(slog.New(slog.DiscardHandler), storage{})
+	owner := core.WorkspaceOwner(workspaceID)
+
+	require.NoError(t, a.CheckQuota(context.Background(), owner, 40))
+	require.ErrorIs(t, a.CheckQuota(context.Background(), owner, 41), service.ErrQuotaExceeded)
+	require.NoError(t, a.CheckQuota(context.Background(), owner, -10))
+	require.NoError(t, a.CheckQuota(context.Background(), "user", 1000))
+}
+
+func TestAccessUserQuota(t *testing.T) {
+	a := New(slog.New(slog.DiscardHandler), storage{})
+	limited := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "user", TenantID: "limited"})
+	unlimited := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "user", TenantID: "other"})
+
+	require.NoError(t, a.CheckQuota(limited, "user", 10))
+	require.ErrorIs(t, a.CheckQuota(limited, "user", 11), service.ErrQuotaExceeded)
+	require.NoError(t, a.CheckQuota(unlimited, "user", 1000))
+	require.NoError(t, a.CheckQuota(owncontext.Unscoped(limited), "user", 1000))
+}
+
+func TestAccessSubtree(t *testing.T) {
+	a := New(slog.New(slog.DiscardHandler), storage{})
+
+	ok, err := a.InSubtree(owncontext.New(context.Background(), "user"), workspaceID)
+	require.NoError(t, err)
+	require.True(t, ok, "unlimited tokens reach every directory")
+
+	ctx := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "user", Subtree: string(rootID)})
+	for id, inside := range map[types.ObjectId]bool{rootID: true, nestedID: true, workspaceID: false} {
+		ok, err := a.InSubtree(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, inside, ok, "%v", id)
+	}
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type Storage interface {
+	ListUsers(ctx context.Context, offset, limit uint) ([]core.UserUsage, error)
+	Get(ctx context.Context, id types.ObjectId) (*core.Directory, error)
+	GetWithPagination(ctx context.Context, id types.ObjectId, offset, limit uint, sort core.Sort) (*core.DirectoryPage, error)
+	GetRoot(ctx context.Context, userID string, offset, limit uint, sort core.Sort) (*core.DirectoryPage, error)
+	Move(ctx context.Context, id, toID types.ObjectId) error
+	GetFile(ctx context.Context, id types.ObjectId) (*core.File, error)
+	MoveFile(ctx context.Context, id, toID types.ObjectId) error
+	ListWorkspaces(ctx context.Context, userID string) ([]core.Workspace, error)
+	GetFileIDs(ctx context.Context, userID string) ([]types.ObjectId, error)
+	DeleteUserData(ctx context.Context, userID string) error
+	RecalculateSizes(ctx context.Context, userID string) (*core.Recalculation, error)
+}
+
+// Service lets admins work on the trees of other users, admins of the default tenant on the ones of every tenant.
+type Service struct {
+	l *slog.Logger
+	s Storage
+	c service.Communicator
+	t service.AuditTrail
+}
+
+func New(l *slog.Logger, s Storage, c service.Communicator, t service.AuditTrail) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.admin.Service"),
+		s: s,
+		c: c,
+		t: t,
+	}
+}
+
+func (s *Service) adminContext(ctx owncontext.Context) (owncontext.Context, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "adminContext"))
+
+	if !ctx.IsAdmin() {
+		return nil, ownerrors.NewForbiddenError(l, "not an admin", "forbidden")
+	}
+	if ctx.TenantID() == "" {
+		return owncontext.Unscoped(ctx), nil
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type TransferRequest struct {
+	Type string         `json:"type" validate:"required,oneof=dir file"`
+	ID   types.ObjectId `json:"id" validate:"required"`
+	// To is the new owner, a user ID or a workspace, see core.WorkspaceOwner
+	To string `json:"to" validate:"required"`
+	// ParentID is the directory of To the item is moved into
+	ParentID types.ObjectId `json:"parentID" validate:"required"`
+}
+
+// Transfer moves a directory or a file into the tree of another user, who owns it from then on.
+// Unlike a move by the user, the quota of the new owner and the locks of files are not checked.
+func (s *Service) Transfer(ctx owncontext.Context, data *TransferRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Transfer"))
+
+	ctx, err := s.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	parent, err := s.s.Get(ctx, data.ParentID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	if parent.UserID != data.To {
+		return ownerrors.NewValidationError(l, "parent of another owner", "the directory does not belong to the new owner")
+	}
+
+	var owner, parentDirectoryID, tenantID string
+	switch data.Type {
+	case core.DirectoryItem:
+		dir, err := s.s.Get(ctx, data.ID)
+		if err != nil {
+			return service.NewDBError(l, err)
+		}
+		if dir.Path == nil {
+			return ownerrors.NewValidationError(l, "root directory", "a root directory can not be transferred")
+		}
+		if parent.Within(dir.ID) {
+			return ownerrors.NewValidationError(l, "move into subtree", "a directory can not be moved into itself")
+		}
+		owner, parentDirectoryID, tenantID = dir.UserID, dir.ParentDirectoryID, dir.TenantID
+	default:
+		file, err := s.s.GetFile(ctx, data.ID)
+		if err != nil {
+			return service.NewDBError(l, err)
+		}
+		owner, parentDirectoryID, tenantID = file.UserID, file.ParentDirectoryID, file.TenantID
+	}
+	if tenantID != parent.TenantID {
+		return ownerrors.NewValidationError(l, "transfer across tenants", "items can not be transferred to another tenant")
+	}
+
+	// the storage hands the item over to the owner of parent as part of the move
+	if data.Type == core.DirectoryItem {
+		err = s.s.Move(ctx, data.ID, parent.ID)
+	} else {
+		err = s.s.MoveFile(ctx, data.ID, parent.ID)
+	}
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditAdminTransfer, []types.ObjectId{data.ID},
+		core.AuditValues{"parentDirectoryID": parentDirectoryID, "owner": owner},
+		core.AuditValues{"parentDirectoryID": parent.ID, "owner": data.To})
+
+	return nil
+}
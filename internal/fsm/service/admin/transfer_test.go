@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	aliceRootID = types.ObjectId("65f000000000000000000001")
+	aliceDirID  = types.ObjectId("65f000000000000000000002")
+	bobRootID   = types.ObjectId("65f000000000000000000003")
+	fileID      = types.ObjectId("65f000000000000000000004")
+)
+
+type storage struct {
+	Storage
+	calls []string
+}
+
+func (s *storage) Get(_ context.Context, id types.ObjectId) (*core.Directory, error) {
+	switch id {
+	case aliceRootID:
+		return &core.Directory{ID: id, UserID: "alice"}, nil
+	case aliceDirID:
+		return &core.Directory{ID: id, UserID: "alice", ParentDirectoryID: string(aliceRootID), Path: []core.PathElement{{ID: aliceRootID}}}, nil
+	case bobRootID:
+		return &core.Directory{ID: id, UserID: "bob"}, nil
+	}
+
+	return nil, errors.New("not found")
+}
+
+func (s *storage) GetFile(_ context.Context, id types.ObjectId) (*core.File, error) {
+	return &core.File{ID: id, UserID: "alice", ParentDirectoryID: string(aliceDirID)}, nil
+}
+
+func (s *storage) Move(_ context.Context, _, _ types.ObjectId) error {
+	s.calls = append(s.calls, "Move")
+
+	return nil
+}
+
+func (s *storage) MoveFile(_ context.Context, _, _ types.ObjectId) error {
+	s.calls = append(s.calls, "MoveFile")
+
+	return nil
+}
+
+type trail struct{}
+
+func (trail) Record(context.Context, string, []types.ObjectId, core.AuditValues, core.AuditValues) {}
+
+func status(err error) int {
+	var userErr ownerrors.UserError
+	if !errors.As(err, &userErr) {
+		return 0
+	}
+
+	return userErr.Status()
+}
+
+func TestTransfer(t *testing.T) {
+	admin := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "root", Admin: true})
+
+	for _, c := range []struct {
+		name   string
+		ctx    owncontext.Context
+		data   TransferRequest
+		status int
+		calls  []string
+	}{
+		{
+			name:   "not an admin",
+			ctx:    owncontext.New(context.Background(), "alice"),
+			data:   TransferRequest{Type: core.DirectoryItem, ID: aliceDirID, To: "bob", ParentID: bobRootID},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "parent of another owner",
+			ctx:    admin,
+			data:   TransferRequest{Type: core.DirectoryItem, ID: aliceDirID, To: "carol", ParentID: bobRootID},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "root directory",
+			ctx:    admin,
+			data:   TransferRequest{Type: core.DirectoryItem, ID: aliceRootID, To: "bob", ParentID: bobRootID},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "into itself",
+			ctx:    admin,
+			data:   TransferRequest{Type: core.DirectoryItem, ID: aliceRootID, To: "alice", ParentID: aliceDirID},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:  "directory",
+			ctx:   admin,
+			data:  TransferRequest{Type: core.DirectoryItem, ID: aliceDirID, To: "bob", ParentID: bobRootID},
+			calls: []string{"Move"},
+		},
+		{
+			name:  "file",
+			ctx:   admin,
+			data:  TransferRequest{Type: core.FileItem, ID: fileID, To: "bob", ParentID: bobRootID},
+			calls: []string{"MoveFile"},
+		},
+		{
+			name:  "file of the same owner",
+			ctx:   admin,
+			data:  TransferRequest{Type: core.FileItem, ID: fileID, To: "alice", ParentID: aliceRootID},
+			calls: []string{"MoveFile"},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s := &storage{}
+			err := New(slog.New(slog.DiscardHandler), s, nil, trail{}).Transfer(c.ctx, &c.data)
+			if c.status != 0 {
+				require.Equal(t, c.status, status(err), "%v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, c.calls, s.calls)
+		})
+	}
+}
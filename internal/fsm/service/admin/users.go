@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/fsm/service/directory"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"time"
+)
+
+const DefaultLimit = 100
+
+type ListUsersRequest struct {
+	Offset uint `query:"offset" validate:"-"`
+	Limit  uint `query:"limit" validate:"max=1000"`
+}
+
+// ListUsers returns the users with their usage, the ones storing the most first.
+func (s *Service) ListUsers(ctx owncontext.Context, data *ListUsersRequest) (*[]core.UserUsage, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "ListUsers"))
+
+	ctx, err := s.adminContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if data.Limit == 0 {
+		data.Limit = DefaultLimit
+	}
+
+	users, err := s.s.ListUsers(ctx, data.Offset, data.Limit)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &users, nil
+}
+
+type TreeRequest struct {
+	UserID string `params:"userID" validate:"required"`
+	// ID is the directory to return, the root of the user if empty
+	ID          types.ObjectId `query:"id" validate:"-"`
+	Offset      uint           `query:"offset" validate:"-"`
+	Limit       uint           `query:"limit" validate:"-"`
+	SortByField string         `query:"sortByField" validate:"omitempty,sortfields"`
+	SortOrder   int            `query:"sortOrder" validate:"oneof=-1 0 1"`
+}
+
+// Tree returns a directory of the user, shortcuts are returned unresolved.
+func (s *Service) Tree(ctx owncontext.Context, data *TreeRequest) (*core.DirectoryPage, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Tree"))
+
+	ctx, err := s.adminContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if data.Limit == 0 {
+		data.Limit = directory.DefaultLimit
+	}
+	if data.SortByField == "" {
+		data.SortByField = directory.DefaultSortField
+	}
+	if data.SortOrder == 0 {
+		data.SortOrder = directory.DefaultSortOrder
+	}
+	sort := core.ParseSort(data.SortByField, data.SortOrder)
+
+	if data.ID.IsZero() {
+		dir, err := s.s.GetRoot(ctx, data.UserID, data.Offset, data.Limit, sort)
+		if err != nil {
+			return nil, service.NewDBError(l, err)
+		}
+		core.DropExpiredLocks(dir.Files, time.Now())
+
+		return dir, nil
+	}
+
+	dir, err := s.s.GetWithPagination(ctx, data.ID, data.Offset, data.Limit, sort)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	if dir.UserID != data.UserID {
+		return nil, ownerrors.NewNotFoundError(l, "directory of another user", "directory not found")
+	}
+	core.DropExpiredLocks(dir.Files, time.Now())
+
+	return dir, nil
+}
+
+type DeleteUserRequest struct {
+	UserID string `params:"userID" validate:"required"`
+}
+
+// DeleteUser removes all data of the user. The content of their files is deleted from the FS first,
+// if that fails the documents are kept, so the deletion can be repeated. The audit trail of the user is kept.
+// The last owner of a workspace can not be deleted, the workspace has to be handed over first.
+func (s *Service) DeleteUser(ctx owncontext.Context, data *DeleteUserRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "DeleteUser"))
+
+	ctx, err := s.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := core.ParseWorkspaceOwner(data.UserID); ok {
+		return ownerrors.NewValidationError(l, "workspace owner", "workspaces are not users")
+	}
+
+	workspaces, err := s.s.ListWorkspaces(ctx, data.UserID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	for _, workspace := range workspaces {
+		if workspace.IsLastOwner(data.UserID) {
+			return ownerrors.NewConflictError(l, "last owner", "the user is the last owner of the workspace "+workspace.Name)
+		}
+	}
+
+	ids, err := s.s.GetFileIDs(ctx, data.UserID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	var errs error
+	for _, id := range ids {
+		if err := s.c.Delete(ctx, id); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("unable to delete content of file %v: %w", id, err))
+		}
+	}
+	if errs != nil {
+		return ownerrors.NewInternalError(l, "unable to delete file content", errs)
+	}
+
+	if err := s.s.DeleteUserData(ctx, data.UserID); err != nil {
+		return service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditAdminPurge, nil, core.AuditValues{"userID": data.UserID, "files": len(ids)}, nil)
+
+	return nil
+}
+
+type RecalculateRequest struct {
+	UserID string `params:"userID" validate:"required"`
+}
+
+// Recalculate corrects the sizes of the directories of the user, e.g. after an interrupted move or delete.
+func (s *Service) Recalculate(ctx owncontext.Context, data *RecalculateRequest) (*core.Recalculation, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Recalculate"))
+
+	ctx, err := s.adminContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.s.RecalculateSizes(ctx, data.UserID)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	if result.Corrected > 0 {
+		s.t.Record(ctx, core.AuditAdminResize, nil, nil, core.AuditValues{
+			"userID": data.UserID, "corrected": result.Corrected, "size": result.Size,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+type userStorage struct {
+	storage
+	workspaces []core.Workspace
+}
+
+func (s *userStorage) ListWorkspaces(_ context.Context, _ string) ([]core.Workspace, error) {
+	return s.workspaces, nil
+}
+
+func (s *userStorage) GetFileIDs(_ context.Context, _ string) ([]types.ObjectId, error) {
+	return []types.ObjectId{fileID}, nil
+}
+
+func (s *userStorage) DeleteUserData(_ context.Context, userID string) error {
+	s.calls = append(s.calls, "DeleteUserData "+userID)
+
+	return nil
+}
+
+// communicator fails to delete the content of files if err is set.
+type communicator struct {
+	service.Communicator
+	calls *[]string
+	err   error
+}
+
+func (c communicator) Delete(_ context.Context, id types.ObjectId) error {
+	*c.calls = append(*c.calls, "Delete "+string(id))
+
+	return c.err
+}
+
+func TestDeleteUser(t *testing.T) {
+	admin := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "root", Admin: true})
+
+	for _, c := range []struct {
+		name       string
+		workspaces []core.Workspace
+		err        error
+		status     int
+		calls      []string
+	}{
+		{
+			name:  "content before documents",
+			calls: []string{"Delete " + string(fileID), "DeleteUserData alice"},
+		},
+		{
+			name:   "content not deleted",
+			err:    errors.New("unavailable"),
+			status: http.StatusInternalServerError,
+			calls:  []string{"Delete " + string(fileID)},
+		},
+		{
+			name: "last owner of a workspace",
+			workspaces: []core.Workspace{{Name: "team", Members: []core.Member{
+				{UserID: "alice", Role: core.RoleOwner},
+				{UserID: "bob", Role: core.RoleEditor},
+			}}},
+			status: http.StatusConflict,
+		},
+		{
+			name: "one of the owners of a workspace",
+			workspaces: []core.Workspace{{Name: "team", Members: []core.Member{
+				{UserID: "alice", Role: core.RoleOwner},
+				{UserID: "bob", Role: core.RoleOwner},
+			}}},
+			calls: []string{"Delete " + string(fileID), "DeleteUserData alice"},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s := &userStorage{workspaces: c.workspaces}
+			fs := communicator{calls: &s.calls, err: c.err}
+			err := New(slog.New(slog.DiscardHandler), s, fs, trail{}).DeleteUser(admin, &DeleteUserRequest{UserID: "alice"})
+			if c.status != 0 {
+				require.Equal(t, c.status, status(err), "%v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, c.calls, s.calls)
+		})
+	}
+}
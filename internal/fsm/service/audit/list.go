@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"io"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	// MaxExportRecords limits a single export, larger ranges are exported in several parts
+	// starting at the createdAt of the last exported record.
+	MaxExportRecords = 100000
+)
+
+var errExportLimit = errors.New("export limit reached")
+
+type Query struct {
+	Actor  string         `query:"actor" validate:"-"`
+	Target types.ObjectId `query:"target" validate:"-"`
+	Action string         `query:"action" validate:"-"`
+	From   time.Time      `query:"from" validate:"-"`
+	To     time.Time      `query:"to" validate:"-"`
+}
+
+func (q *Query) ToCore() core.AuditQuery {
+	return core.AuditQuery{
+		Actor:  q.Actor,
+		Target: q.Target,
+		Action: q.Action,
+		From:   q.From,
+		To:     q.To,
+	}
+}
+
+type ListRequest struct {
+	Query
+	Offset uint `query:"offset" validate:"-"`
+	Limit  uint `query:"limit" validate:"max=1000"`
+}
+
+// List returns the records of the audit trail matching the query, the latest first.
+func (s *Service) List(ctx owncontext.Context, data *ListRequest) (*[]core.AuditRecord, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	ctx, err := s.adminContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if data.Limit == 0 {
+		data.Limit = DefaultLimit
+	}
+
+	records, err := s.s.List(ctx, data.ToCore(), data.Offset, data.Limit)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &records, nil
+}
+
+type ExportRequest struct {
+	Query
+}
+
+// ExportTrailer is the last line of an export cut off after MaxExportRecords records,
+// the next part starts at From, the createdAt of the last exported record.
+type ExportTrailer struct {
+	Truncated bool      `json:"truncated"`
+	From      time.Time `json:"from"`
+}
+
+// Export writes the records matching the query as JSON Lines, the oldest first, at most MaxExportRecords of them.
+// If more records match, an ExportTrailer follows them.
+func (s *Service) Export(ctx owncontext.Context, data *ExportRequest, w io.Writer) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Export"))
+
+	ctx, err := s.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		encoder = json.NewEncoder(w)
+		count   int
+		last    time.Time
+	)
+	err = s.s.Export(ctx, data.ToCore(), func(record *core.AuditRecord) error {
+		if count == MaxExportRecords {
+			return errExportLimit
+		}
+		count++
+		last = record.CreatedAt
+
+		return encoder.Encode(record)
+	})
+	if errors.Is(err, errExportLimit) {
+		return encoder.Encode(ExportTrailer{Truncated: true, From: last})
+	}
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+
+	return nil
+}
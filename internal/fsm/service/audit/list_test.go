@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/stretchr/testify/require"
+)
+
+// storage exports count records, one second apart.
+type storage struct {
+	Storage
+	count int
+}
+
+func (s storage) Export(_ context.Context, _ core.AuditQuery, f func(record *core.AuditRecord) error) error {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range s.count {
+		if err := f(&core.AuditRecord{Action: core.AuditFileRename, CreatedAt: start.Add(time.Duration(i) * time.Second)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func export(t *testing.T, count int) []string {
+	admin := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "root", Admin: true})
+
+	var buf bytes.Buffer
+	require.NoError(t, New(slog.New(slog.DiscardHandler), storage{count: count}).Export(admin, &ExportRequest{}, &buf))
+
+	var lines []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines
+}
+
+func TestExport(t *testing.T) {
+	require.Len(t, export(t, 3), 3)
+	require.Len(t, export(t, MaxExportRecords), MaxExportRecords)
+
+	lines := export(t, MaxExportRecords+1)
+	require.Len(t, lines, MaxExportRecords+1)
+
+	var trailer ExportTrailer
+	require.NoError(t, json.Unmarshal([]byte(lines[MaxExportRecords]), &trailer))
+	require.True(t, trailer.Truncated)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, MaxExportRecords-1, 0, time.UTC), trailer.From)
+}
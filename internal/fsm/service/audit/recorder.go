@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"time"
+)
+
+const (
+	maxBatchSize = 100
+	writeTimeout = 10 * time.Second
+	// bufferTimeout is how long Record waits for room in a full buffer before appending the record itself
+	bufferTimeout = time.Second
+)
+
+type Writer interface {
+	Append(ctx context.Context, records []core.AuditRecord) error
+}
+
+// Recorder buffers audit records and appends them in batches in the background, so recording
+// does not delay a request. When the buffer stays full, e.g. while the writer is slow, records are appended synchronously.
+type Recorder struct {
+	l       *slog.Logger
+	w       Writer
+	records chan core.AuditRecord
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewRecorder(l *slog.Logger, cfg *config.Config, w Writer) *Recorder {
+	return &Recorder{
+		l:       l.With("module", "internal.fsm.service.audit.Recorder"),
+		w:       w,
+		records: make(chan core.AuditRecord, cfg.AuditBufferSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func (r *Recorder) Record(ctx context.Context, action string, targets []types.ObjectId, before, after core.AuditValues) {
+	record := core.AuditRecord{
+		Action:    action,
+		Targets:   targets,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now(),
+	}
+	if c, ok := ctx.(owncontext.Context); ok {
+		record.Actor = c.UserID()
+		record.TenantID = c.TenantID()
+		record.ClientIP = c.ClientIP()
+		record.RequestID = c.RequestID()
+	}
+
+	select {
+	case r.records <- record:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(bufferTimeout)
+	defer timer.Stop()
+
+	select {
+	case r.records <- record:
+	case <-timer.C:
+		r.l.Warn("audit buffer is full, appending record synchronously", slog.String("action", record.Action))
+		r.write([]core.AuditRecord{record})
+	}
+}
+
+func (r *Recorder) Start(_ context.Context) error {
+	go r.run()
+
+	return nil
+}
+
+// Stop appends the buffered records and waits for the background writer to finish.
+func (r *Recorder) Stop(ctx context.Context) error {
+	close(r.stop)
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	for {
+		select {
+		case record := <-r.records:
+			r.write(r.batch(record))
+		case <-r.stop:
+			for {
+				select {
+				case record := <-r.records:
+					r.write(r.batch(record))
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// batch collects the records already waiting in the buffer after first.
+func (r *Recorder) batch(first core.AuditRecord) []core.AuditRecord {
+	batch := []core.AuditRecord{first}
+	for len(batch) < maxBatchSize {
+		select {
+		case record := <-r.records:
+			batch = append(batch, record)
+		default:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+func (r *Recorder) write(batch []core.AuditRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := r.w.Append(ctx, batch); err != nil {
+		r.l.Error("unable to append audit records", slog.Any("records", batch), slog.String("err", err.Error()))
+	}
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+type writerFunc func(records []core.AuditRecord)
+
+func (f writerFunc) Append(_ context.Context, records []core.AuditRecord) error {
+	f(records)
+	return nil
+}
+
+func TestRecorderTakesActorFromContext(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		recorded []core.AuditRecord
+	)
+	cfg := &config.Config{Audit: config.Audit{AuditBufferSize: 10}}
+	r := NewRecorder(slog.New(slog.DiscardHandler), cfg, writerFunc(func(records []core.AuditRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+		recorded = append(recorded, records...)
+	}))
+
+	ctx := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{
+		UserID:    "user",
+		TenantID:  "tenant",
+		ClientIP:  "10.0.0.1",
+		RequestID: "request",
+	})
+	target := types.ObjectId("65f000000000000000000001")
+	r.Record(ctx, core.AuditFileRename, []types.ObjectId{target}, core.AuditValues{"name": "a"}, core.AuditValues{"name": "b"})
+	r.Record(context.Background(), core.AuditFSCallback, nil, nil, nil)
+
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Stop(context.Background()))
+
+	require.Len(t, recorded, 2)
+	require.Equal(t, "user", recorded[0].Actor)
+	require.Equal(t, "tenant", recorded[0].TenantID)
+	require.Equal(t, "10.0.0.1", recorded[0].ClientIP)
+	require.Equal(t, "request", recorded[0].RequestID)
+	require.Equal(t, []types.ObjectId{target}, recorded[0].Targets)
+	require.Equal(t, "b", recorded[0].After["name"])
+	require.Empty(t, recorded[1].Actor)
+}
+
+func TestRecorderAppendsSynchronouslyWhenFull(t *testing.T) {
+	var recorded []core.AuditRecord
+	cfg := &config.Config{Audit: config.Audit{AuditBufferSize: 1}}
+	r := NewRecorder(slog.New(slog.DiscardHandler), cfg, writerFunc(func(records []core.AuditRecord) {
+		recorded = append(recorded, records...)
+	}))
+
+	// nothing drains the buffer before Start, the second record waits bufferTimeout for room
+	r.Record(context.Background(), core.AuditFileRename, nil, nil, nil)
+	r.Record(context.Background(), core.AuditFileMove, nil, nil, nil)
+	require.Len(t, recorded, 1)
+	require.Equal(t, core.AuditFileMove, recorded[0].Action)
+
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Stop(context.Background()))
+	require.Len(t, recorded, 2)
+	require.Equal(t, core.AuditFileRename, recorded[1].Action)
+}
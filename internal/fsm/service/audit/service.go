@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"log/slog"
+)
+
+type Storage interface {
+	List(ctx context.Context, query core.AuditQuery, offset, limit uint) ([]core.AuditRecord, error)
+	Export(ctx context.Context, query core.AuditQuery, f func(record *core.AuditRecord) error) error
+}
+
+type Service struct {
+	l *slog.Logger
+	s Storage
+}
+
+func New(l *slog.Logger, s Storage) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.audit.Service"),
+		s: s,
+	}
+}
+
+// adminContext allows admins to read the audit trail, admins of the default tenant the one of every tenant.
+func (s *Service) adminContext(ctx owncontext.Context) (owncontext.Context, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "adminContext"))
+
+	if !ctx.IsAdmin() {
+		return nil, ownerrors.NewForbiddenError(l, "not an admin", "forbidden")
+	}
+	if ctx.TenantID() == "" {
+		return owncontext.Unscoped(ctx), nil
+	}
+
+	return ctx, nil
+}
@@ -11,13 +11,7 @@ import (
 
 type Creator interface {
 	Create(ctx context.Context, parentDirID types.ObjectId, userID, name string) (*core.Directory, error)
-	CreateRoot(
-		ctx context.Context,
-		userID string,
-		offset, limit uint,
-		sortByField string,
-		sortOrder int,
-	) (*core.Directory, error)
+	CreateRoot(ctx context.Context, userID string, offset, limit uint, sort core.Sort) (*core.DirectoryPage, error)
 }
 
 type CreateRequest struct {
@@ -26,20 +20,26 @@ type CreateRequest struct {
 }
 
 func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*core.Directory, error) {
-	l := s.l.With(slog.String("op", "Create"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Create"))
 
-	dir, err := s.s.Create(ctx, data.ParentDirectoryID, ctx.UserID(), data.Name)
+	parent, err := s.getAndCheckOwner(ctx, data.ParentDirectoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := s.s.Create(ctx, data.ParentDirectoryID, parent.UserID, data.Name)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
+	s.t.Record(ctx, core.AuditDirCreate, []types.ObjectId{dir.ID, parent.ID}, nil, core.AuditValues{"name": dir.Name, "owner": dir.UserID})
 
 	return dir, nil
 }
 
-func (s *Service) initUser(ctx context.Context, userID string, offset, limit uint, sortByField string, sortOrder int) (*core.Directory, error) {
+func (s *Service) initUser(ctx context.Context, userID string, offset, limit uint, sort core.Sort) (*core.DirectoryPage, error) {
 	l := s.l.With(slog.String("op", "initUser"))
 
-	dir, err := s.s.CreateRoot(ctx, userID, offset, limit, sortByField, sortOrder)
+	dir, err := s.s.CreateRoot(ctx, userID, offset, limit, sort)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
@@ -18,27 +18,49 @@ type Deleter interface {
 }
 
 type DeleteRequest struct {
+	service.IfMatch
+
 	ID types.ObjectId `params:"id" validate:"required"`
 }
 
 func (s *Service) Delete(ctx owncontext.Context, data *DeleteRequest) error {
-	l := s.l.With(slog.String("op", "Delete"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Delete"))
 
 	dir, err := s.s.Get(ctx, data.ID)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
 
-	if dir.UserID != ctx.UserID() {
-		return service.NewWrongUserError(l)
+	if err := service.CheckWrite(l, s.a, ctx, dir.UserID); err != nil {
+		return err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+		return err
+	}
+	ctx, err = data.CheckRevision(l, ctx, dir.ID, dir.Revision)
+	if err != nil {
+		return err
+	}
+	if err := s.checkLocks(l, ctx, dir); err != nil {
+		return err
 	}
 
 	err = s.s.Delete(ctx, dir.ID)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
+	s.t.Record(ctx, core.AuditDirDelete, []types.ObjectId{dir.ID}, core.AuditValues{
+		"name":              dir.Name,
+		"parentDirectoryID": dir.ParentDirectoryID,
+		"owner":             dir.UserID,
+		"size":              dir.Size,
+	}, nil)
 
 	go func() {
+		if err := s.s.StupidDeleteShortcuts(context.Background(), dir.ID); err != nil {
+			l.Error("unable to delete shortcuts in the background", slog.String("err", err.Error()))
+		}
+
 		err := s.deleteDir(context.Background(), dir.Directories, dir.Files)
 		if err != nil {
 			l.Error("unable to delete dir in the background", slog.String("err", err.Error()))
@@ -67,11 +89,12 @@ func (s *Service) deleteDir(ctx context.Context, dirs []core.Directory, files []
 
 	var errs error
 	for _, dir := range dirs {
-		errors.Join(errs, s.s.StupidDelete(ctx, dir.ID))
+		errs = errors.Join(errs, s.s.StupidDelete(ctx, dir.ID))
+		errs = errors.Join(errs, s.s.StupidDeleteShortcuts(ctx, dir.ID))
 	}
 	for _, file := range files {
-		errors.Join(errs, s.s.StupidDeleteFile(ctx, file.ID))
-		errors.Join(errs, s.c.Delete(ctx, file.ID))
+		errs = errors.Join(errs, s.s.StupidDeleteFile(ctx, file.ID))
+		errs = errors.Join(errs, s.c.Delete(ctx, file.ID))
 	}
 	if errs != nil {
 		return service.NewDBError(l, errs)
@@ -7,30 +7,33 @@ import (
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/mbretter/go-mongodb/types"
 	"log/slog"
+	"time"
 )
 
 const (
 	DefaultLimit     = 300
-	DefaultSortField = "name"
+	DefaultSortField = "type,name"
 	DefaultSortOrder = 1
 )
 
 type Getter interface {
 	Get(ctx context.Context, id types.ObjectId) (*core.Directory, error)
-	GetWithPagination(ctx context.Context, id types.ObjectId, offset, limit uint, sortByField string, sortOrder int) (*core.Directory, error)
-	GetRoot(ctx context.Context, userID string, offset, limit uint, sortByField string, sortOrder int) (*core.Directory, error)
+	GetWithPagination(ctx context.Context, id types.ObjectId, offset, limit uint, sort core.Sort) (*core.DirectoryPage, error)
+	GetRoot(ctx context.Context, userID string, offset, limit uint, sort core.Sort) (*core.DirectoryPage, error)
 }
 
 type GetRequest struct {
+	service.IfNoneMatch
+
 	ID          types.ObjectId `params:"id" validate:"-"`
 	Offset      uint           `query:"offset" validate:"-"`
 	Limit       uint           `query:"limit" validate:"-"`
-	SortByField string         `query:"sortByField" validate:"-"`
-	SortOrder   int            `query:"sortOrder" validate:"-"`
+	SortByField string         `query:"sortByField" validate:"omitempty,sortfields"`
+	SortOrder   int            `query:"sortOrder" validate:"oneof=-1 0 1"`
 }
 
-func (s *Service) Get(ctx owncontext.Context, data *GetRequest) (*core.Directory, error) {
-	l := s.l.With(slog.String("op", "Get"))
+func (s *Service) Get(ctx owncontext.Context, data *GetRequest) (*core.DirectoryPage, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Get"))
 
 	if data.Limit == 0 {
 		data.Limit = DefaultLimit
@@ -41,56 +44,85 @@ func (s *Service) Get(ctx owncontext.Context, data *GetRequest) (*core.Directory
 	if data.SortOrder == 0 {
 		data.SortOrder = DefaultSortOrder
 	}
+	sort := core.ParseSort(data.SortByField, data.SortOrder)
 
+	// the root of a token limited to a subtree is the subtree
+	if data.ID.IsZero() && ctx.Subtree() != "" {
+		data.ID = types.ObjectId(ctx.Subtree())
+	}
 	if data.ID.IsZero() {
-		dir, err := s.s.GetRoot(ctx, ctx.UserID(), data.Offset, data.Limit, data.SortByField, data.SortOrder)
+		dir, err := s.s.GetRoot(ctx, ctx.UserID(), data.Offset, data.Limit, sort)
 		if isErrNotFound(err) {
-			dir, err = s.s.CreateRoot(ctx, ctx.UserID(), data.Offset, data.Limit, data.SortByField, data.SortOrder)
+			dir, err = s.s.CreateRoot(ctx, ctx.UserID(), data.Offset, data.Limit, sort)
 		}
 		if err != nil {
 			return nil, service.NewDBError(l, err)
 		}
+		if err := data.CheckRevision(l, dir.Revision); err != nil {
+			return nil, err
+		}
+		if err := s.resolveShortcuts(ctx, dir.Shortcuts); err != nil {
+			return nil, service.NewDBError(l, err)
+		}
+		core.DropExpiredLocks(dir.Files, time.Now())
 
 		return dir, nil
 	}
 
-	dir, err := s.s.GetWithPagination(ctx, data.ID, data.Offset, data.Limit, data.SortByField, data.SortOrder)
+	dir, err := s.s.GetWithPagination(ctx, data.ID, data.Offset, data.Limit, sort)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
 
-	if dir.UserID != ctx.UserID() && !dir.Public {
-		return nil, service.NewWrongUserError(l)
+	if err := service.CheckRead(l, s.a, ctx, dir.UserID, dir.Public); err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+		return nil, err
 	}
+	if err := data.CheckRevision(l, dir.Revision); err != nil {
+		return nil, err
+	}
+	if err := s.resolveShortcuts(ctx, dir.Shortcuts); err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditDirOpen, []types.ObjectId{dir.ID}, nil, nil)
+	core.DropExpiredLocks(dir.Files, time.Now())
 
 	return dir, nil
 }
 
 func (s *Service) getAndCheckUser(ctx owncontext.Context, id types.ObjectId) (*core.Directory, error) {
-	l := s.l.With(slog.String("op", "getAndCheckUser"))
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckUser"))
 
 	dir, err := s.s.Get(ctx, id)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
 
-	if dir.UserID != ctx.UserID() && !dir.Public {
-		return nil, service.NewWrongUserError(l)
+	if err := service.CheckRead(l, s.a, ctx, dir.UserID, dir.Public); err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+		return nil, err
 	}
 
 	return dir, nil
 }
 
 func (s *Service) getAndCheckOwner(ctx owncontext.Context, id types.ObjectId) (*core.Directory, error) {
-	l := s.l.With(slog.String("op", "getAndCheckUser"))
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckUser"))
 
 	dir, err := s.s.Get(ctx, id)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
 
-	if dir.UserID != ctx.UserID() {
-		return nil, service.NewWrongUserError(l)
+	if err := service.CheckWrite(l, s.a, ctx, dir.UserID); err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+		return nil, err
 	}
 
 	return dir, nil
@@ -0,0 +1,27 @@
+package directory
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type LockChecker interface {
+	LockedFor(ctx context.Context, id types.ObjectId, userID string) (bool, error)
+}
+
+// checkLocks returns an error if another user holds a lock on a file in the directory or below it.
+func (s *Service) checkLocks(l *slog.Logger, ctx owncontext.Context, dir *core.Directory) error {
+	locked, err := s.s.LockedFor(ctx, dir.ID, ctx.UserID())
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	if locked {
+		return service.NewLockedError(l)
+	}
+
+	return nil
+}
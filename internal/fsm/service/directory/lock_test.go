@@ -0,0 +1,37 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/stretchr/testify/require"
+)
+
+func status(err error) int {
+	var userErr ownerrors.UserError
+	if !errors.As(err, &userErr) {
+		return 0
+	}
+
+	return userErr.Status()
+}
+
+// TestLockedSubtree checks that a directory with a file locked by another user below it is neither deleted nor moved,
+// the fake storage has neither Delete nor Move.
+func TestLockedSubtree(t *testing.T) {
+	s := &storage{dir: &core.Directory{ID: dirID, UserID: "user"}, locked: "other"}
+	dirs := New(slog.New(slog.DiscardHandler), s, nil, access{owners: map[string]bool{"user": true}}, trail{})
+	ctx := owncontext.New(context.Background(), "user")
+
+	err := dirs.Delete(ctx, &DeleteRequest{ID: dirID})
+	require.Equal(t, http.StatusLocked, status(err), "%v", err)
+
+	err = dirs.Move(ctx, &MoveRequest{ID: dirID, To: dirID})
+	require.Equal(t, http.StatusLocked, status(err), "%v", err)
+}
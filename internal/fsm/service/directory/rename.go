@@ -2,6 +2,7 @@ package directory
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/mbretter/go-mongodb/types"
@@ -13,22 +14,29 @@ type Renamer interface {
 }
 
 type RenameRequest struct {
+	service.IfMatch
+
 	ID   types.ObjectId `params:"id" validate:"required"`
 	Name string         `query:"name" validate:"required"`
 }
 
 func (s *Service) Rename(ctx owncontext.Context, data *RenameRequest) error {
-	l := s.l.With(slog.String("op", "Rename"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Rename"))
 
 	dir, err := s.getAndCheckOwner(ctx, data.ID)
 	if err != nil {
 		return err
 	}
+	ctx, err = data.CheckRevision(l, ctx, dir.ID, dir.Revision)
+	if err != nil {
+		return err
+	}
 
 	err = s.s.Rename(ctx, dir.ID, data.Name)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
+	s.t.Record(ctx, core.AuditDirRename, []types.ObjectId{dir.ID}, core.AuditValues{"name": dir.Name}, core.AuditValues{"name": data.Name})
 
 	return nil
 }
@@ -2,39 +2,79 @@ package directory
 
 import (
 	"context"
+	"fmt"
 	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
 	"go.mongodb.org/mongo-driver/bson"
 	"log/slog"
+	"strings"
+	"time"
 )
 
+const DefaultRelevanceSortField = "-relevance,type,name"
+
 type Searcher interface {
 	GetGlobalWithPaginationAndFiltering(
 		ctx context.Context,
 		userID string,
+		query string,
 		directoryFilter bson.D,
 		fileFilter bson.D,
 		offset, limit uint,
-		sortByField string,
-		sortOrder int,
+		sort core.Sort,
 	) (*core.DirectoryLike, error)
+	GetSubtreeIDs(ctx context.Context, id types.ObjectId) ([]types.ObjectId, error)
+	GetByPath(ctx context.Context, userID string, names []string) (*core.Directory, error)
 }
 
 type SearchRequest struct {
 	Offset      uint   `query:"offset" validate:"-"`
 	Limit       uint   `query:"limit" validate:"-"`
-	SortByField string `query:"sortByField" validate:"-"`
-	SortOrder   int    `query:"sortOrder" validate:"-"`
+	SortByField string `query:"sortByField" validate:"omitempty,searchsortfields"`
+	SortOrder   int    `query:"sortOrder" validate:"oneof=-1 0 1"`
+	Query       string `query:"q" validate:"-"` // see core.Filter.ApplyQuery
+	// Workspace searches the tree of the workspace instead of the personal one
+	Workspace types.ObjectId `query:"workspace" validate:"-"`
 	core.Filter
 }
 
 func (s *Service) Search(ctx owncontext.Context, data *SearchRequest) (*core.DirectoryLike, error) {
-	l := s.l.With(slog.String("op", "Get"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Search"))
+
+	if data.Query != "" {
+		if err := data.Filter.ApplyQuery(data.Query, time.Now()); err != nil {
+			return nil, ownerrors.NewValidationError(l, "unable to parse query", err.Error(), err)
+		}
+	}
+	owner := ctx.UserID()
+	if !data.Workspace.IsZero() {
+		owner = core.WorkspaceOwner(data.Workspace)
+		if err := service.CheckRead(l, s.a, ctx, owner, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if data.InPath != "" {
+		dir, err := s.s.GetByPath(ctx, owner, strings.Split(strings.Trim(data.InPath, "/"), "/"))
+		if err != nil {
+			return nil, ownerrors.NewNotFoundError(l, "unable to resolve path", fmt.Sprintf("directory %v not found", data.InPath), err)
+		}
+		data.In = dir.ID
+	}
+	// a token limited to a subtree searches the subtree
+	if data.In.IsZero() && ctx.Subtree() != "" {
+		data.In = types.ObjectId(ctx.Subtree())
+	}
 
 	if data.Limit == 0 {
 		data.Limit = DefaultLimit
 	}
+	if data.SortByField == "" && data.Name != "" {
+		data.SortByField = DefaultRelevanceSortField
+	}
 	if data.SortByField == "" {
 		data.SortByField = DefaultSortField
 	}
@@ -42,9 +82,31 @@ func (s *Service) Search(ctx owncontext.Context, data *SearchRequest) (*core.Dir
 		data.SortOrder = DefaultSortOrder
 	}
 
+	sort := core.ParseSort(data.SortByField, data.SortOrder)
+
+	if !data.In.IsZero() {
+		dir, err := s.s.Get(ctx, data.In)
+		if err != nil {
+			return nil, service.NewDBError(l, err)
+		}
+		if err := service.CheckRead(l, s.a, ctx, dir.UserID, false); err != nil {
+			return nil, err
+		}
+		if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+			return nil, err
+		}
+		owner = dir.UserID
+
+		subtree, err := s.s.GetSubtreeIDs(ctx, data.In)
+		if err != nil {
+			return nil, service.NewDBError(l, err)
+		}
+		data.Subtree = subtree
+	}
+
 	directoryFilter, fileFilter := data.Filter.ToMongoFilters()
 
-	dir, err := s.s.GetGlobalWithPaginationAndFiltering(ctx, ctx.UserID(), directoryFilter, fileFilter, data.Offset, data.Limit, data.SortByField, data.SortOrder)
+	dir, err := s.s.GetGlobalWithPaginationAndFiltering(ctx, owner, data.Name, directoryFilter, fileFilter, data.Offset, data.Limit, sort)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
@@ -12,21 +12,28 @@ type Storage interface {
 	Renamer
 	Mover
 	Sharer
+	Tagger
+	ShortcutResolver
 	Starer
 	Searcher
+	LockChecker
 }
 
 type Service struct {
 	l *slog.Logger
 	s Storage
 	c service.Communicator
+	a service.Access
+	t service.AuditTrail
 }
 
-func New(l *slog.Logger, s Storage, c service.Communicator) *Service {
+func New(l *slog.Logger, s Storage, c service.Communicator, a service.Access, t service.AuditTrail) *Service {
 	return &Service{
 		l: l.With("module", "internal.fsm.service.directory.Service"),
 		s: s,
 		c: c,
+		a: a,
+		t: t,
 	}
 }
 
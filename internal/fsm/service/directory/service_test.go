@@ -0,0 +1,68 @@
+package directory
+
+import (
+	"context"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+)
+
+var dirID = types.ObjectId("65f000000000000000000001")
+
+type storage struct {
+	Storage
+	dir         *core.Directory
+	directories map[types.ObjectId]*core.Directory
+	files       map[types.ObjectId]*core.File
+	// locked is the user holding a lock below every directory
+	locked string
+}
+
+func (s *storage) Get(_ context.Context, _ types.ObjectId) (*core.Directory, error) {
+	dir := *s.dir
+
+	return &dir, nil
+}
+
+func (s *storage) Star(_ context.Context, _ types.ObjectId, starred bool) error {
+	s.dir.Starred = starred
+
+	return nil
+}
+
+func (s *storage) LockedFor(_ context.Context, _ types.ObjectId, userID string) (bool, error) {
+	return s.locked != "" && s.locked != userID, nil
+}
+
+func (s *storage) GetShortcutTargets(
+	_ context.Context,
+	_, _ []types.ObjectId,
+) (map[types.ObjectId]*core.Directory, map[types.ObjectId]*core.File, error) {
+	return s.directories, s.files, nil
+}
+
+// access lets the user read and change the items of owners, every directory is inside the subtree
+// of the token except for the ones in outside.
+type access struct {
+	service.Access
+	owners  map[string]bool
+	outside map[types.ObjectId]bool
+}
+
+func (a access) CanRead(_ owncontext.Context, owner string) (bool, error) {
+	return a.owners[owner], nil
+}
+
+func (a access) CanWrite(_ owncontext.Context, owner string) (bool, error) {
+	return a.owners[owner], nil
+}
+
+func (a access) InSubtree(_ owncontext.Context, id types.ObjectId) (bool, error) {
+	return !a.outside[id], nil
+}
+
+type trail struct{}
+
+func (trail) Record(context.Context, string, []types.ObjectId, core.AuditValues, core.AuditValues) {}
@@ -0,0 +1,78 @@
+package directory
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+)
+
+type ShortcutResolver interface {
+	GetShortcutTargets(
+		ctx context.Context,
+		directoryIDs, fileIDs []types.ObjectId,
+	) (map[types.ObjectId]*core.Directory, map[types.ObjectId]*core.File, error)
+	StupidDeleteShortcuts(ctx context.Context, parentID types.ObjectId) error
+}
+
+// resolveShortcuts attaches the targets of the shortcuts readable by the user.
+func (s *Service) resolveShortcuts(ctx owncontext.Context, shortcuts []core.Shortcut) error {
+	if len(shortcuts) == 0 {
+		return nil
+	}
+
+	var directoryIDs, fileIDs []types.ObjectId
+	for _, shortcut := range shortcuts {
+		if shortcut.TargetType == core.DirectoryItem {
+			directoryIDs = append(directoryIDs, shortcut.TargetID)
+		} else {
+			fileIDs = append(fileIDs, shortcut.TargetID)
+		}
+	}
+
+	directories, files, err := s.s.GetShortcutTargets(ctx, directoryIDs, fileIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range shortcuts {
+		shortcut := &shortcuts[i]
+		shortcut.Status = core.ShortcutDangling
+
+		switch shortcut.TargetType {
+		case core.DirectoryItem:
+			if directory, ok := directories[shortcut.TargetID]; ok {
+				shortcut.Status = core.ShortcutForbidden
+				if ok, err := s.canRead(ctx, directory.UserID, directory.Public, directory.ID); err != nil {
+					return err
+				} else if ok {
+					shortcut.Status = core.ShortcutResolved
+					shortcut.Directory = directory
+				}
+			}
+		case core.FileItem:
+			if file, ok := files[shortcut.TargetID]; ok {
+				shortcut.Status = core.ShortcutForbidden
+				if ok, err := s.canRead(ctx, file.UserID, file.Public, types.ObjectId(file.ParentDirectoryID)); err != nil {
+					return err
+				} else if ok {
+					shortcut.Status = core.ShortcutResolved
+					shortcut.File = file
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) canRead(ctx owncontext.Context, owner string, public bool, directoryID types.ObjectId) (bool, error) {
+	if ok, err := s.a.InSubtree(ctx, directoryID); err != nil || !ok {
+		return false, err
+	}
+	if public {
+		return true, nil
+	}
+
+	return s.a.CanRead(ctx, owner)
+}
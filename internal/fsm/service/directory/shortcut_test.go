@@ -0,0 +1,67 @@
+package directory
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveShortcuts(t *testing.T) {
+	var (
+		ownID     = types.ObjectId("65f000000000000000000011")
+		publicID  = types.ObjectId("65f000000000000000000012")
+		privateID = types.ObjectId("65f000000000000000000013")
+		outsideID = types.ObjectId("65f000000000000000000014")
+		deletedID = types.ObjectId("65f000000000000000000015")
+		fileID    = types.ObjectId("65f000000000000000000016")
+	)
+	s := &storage{
+		directories: map[types.ObjectId]*core.Directory{
+			ownID:     {ID: ownID, UserID: "user"},
+			publicID:  {ID: publicID, UserID: "other", Public: true},
+			privateID: {ID: privateID, UserID: "other"},
+			outsideID: {ID: outsideID, UserID: "user"},
+		},
+		files: map[types.ObjectId]*core.File{
+			fileID: {ID: fileID, UserID: "user", ParentDirectoryID: string(ownID)},
+		},
+	}
+	a := access{owners: map[string]bool{"user": true}, outside: map[types.ObjectId]bool{outsideID: true}}
+	dirs := New(slog.New(slog.DiscardHandler), s, nil, a, trail{})
+
+	shortcuts := []core.Shortcut{
+		{TargetID: ownID, TargetType: core.DirectoryItem},
+		{TargetID: publicID, TargetType: core.DirectoryItem},
+		{TargetID: privateID, TargetType: core.DirectoryItem},
+		{TargetID: outsideID, TargetType: core.DirectoryItem},
+		{TargetID: deletedID, TargetType: core.DirectoryItem},
+		{TargetID: fileID, TargetType: core.FileItem},
+		{TargetID: deletedID, TargetType: core.FileItem},
+	}
+	require.NoError(t, dirs.resolveShortcuts(owncontext.New(context.Background(), "user"), shortcuts))
+
+	var statuses []string
+	for _, shortcut := range shortcuts {
+		statuses = append(statuses, shortcut.Status)
+		if shortcut.Status != core.ShortcutResolved {
+			require.Nil(t, shortcut.Directory, "unreadable targets are not attached")
+			require.Nil(t, shortcut.File, "unreadable targets are not attached")
+		}
+	}
+	require.Equal(t, []string{
+		core.ShortcutResolved,
+		core.ShortcutResolved,
+		core.ShortcutForbidden,
+		core.ShortcutForbidden,
+		core.ShortcutDangling,
+		core.ShortcutResolved,
+		core.ShortcutDangling,
+	}, statuses)
+	require.Equal(t, ownID, shortcuts[0].Directory.ID)
+	require.Equal(t, fileID, shortcuts[5].File.ID)
+}
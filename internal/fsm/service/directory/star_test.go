@@ -0,0 +1,22 @@
+package directory
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStar checks that the directory gets the sent state, the cases are covered by the file TestStar.
+func TestStar(t *testing.T) {
+	s := &storage{dir: &core.Directory{ID: dirID, UserID: "user", Starred: true}}
+	dirs := New(slog.New(slog.DiscardHandler), s, nil, access{owners: map[string]bool{"user": true}}, trail{})
+	ctx := owncontext.New(context.Background(), "user")
+	starred := false
+
+	require.NoError(t, dirs.Star(ctx, &StarRequest{ID: dirID, Starred: &starred}))
+	require.False(t, s.dir.Starred)
+}
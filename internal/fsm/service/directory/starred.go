@@ -0,0 +1,26 @@
+package directory
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+)
+
+type StarredRequest struct {
+	Offset      uint   `query:"offset" validate:"-"`
+	Limit       uint   `query:"limit" validate:"-"`
+	SortByField string `query:"sortByField" validate:"omitempty,sortfields"`
+	SortOrder   int    `query:"sortOrder" validate:"oneof=-1 0 1"`
+}
+
+// Starred lists the starred directories and files of the user merged into one page.
+func (s *Service) Starred(ctx owncontext.Context, data *StarredRequest) (*core.DirectoryLike, error) {
+	starred := true
+
+	return s.Search(ctx, &SearchRequest{
+		Offset:      data.Offset,
+		Limit:       data.Limit,
+		SortByField: data.SortByField,
+		SortOrder:   data.SortOrder,
+		Filter:      core.Filter{Starred: &starred},
+	})
+}
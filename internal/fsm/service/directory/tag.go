@@ -0,0 +1,85 @@
+package directory
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type Tagger interface {
+	GetTag(ctx context.Context, id types.ObjectId) (*core.Tag, error)
+	AddTag(ctx context.Context, id types.ObjectId, tag core.TagRef) error
+	RemoveTag(ctx context.Context, id, tagID types.ObjectId) error
+}
+
+type TagRequest struct {
+	service.IfMatch
+
+	ID    types.ObjectId `params:"id" validate:"required"`
+	TagID types.ObjectId `params:"tagID" validate:"required"`
+}
+
+func (s *Service) AddTag(ctx owncontext.Context, data *TagRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "AddTag"))
+
+	dir, err := s.s.Get(ctx, data.ID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	if err := service.CheckWrite(l, s.a, ctx, dir.UserID); err != nil {
+		return err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+		return err
+	}
+	ctx, err = data.CheckRevision(l, ctx, dir.ID, dir.Revision)
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.s.GetTag(ctx, data.TagID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	if tag.UserID != ctx.UserID() {
+		return service.NewWrongUserError(l)
+	}
+
+	err = s.s.AddTag(ctx, data.ID, tag.Ref())
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditDirTag, []types.ObjectId{dir.ID, tag.ID}, nil, core.AuditValues{"tag": tag.Name})
+
+	return nil
+}
+
+func (s *Service) RemoveTag(ctx owncontext.Context, data *TagRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "RemoveTag"))
+
+	dir, err := s.s.Get(ctx, data.ID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	if err := service.CheckWrite(l, s.a, ctx, dir.UserID); err != nil {
+		return err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+		return err
+	}
+	ctx, err = data.CheckRevision(l, ctx, dir.ID, dir.Revision)
+	if err != nil {
+		return err
+	}
+
+	err = s.s.RemoveTag(ctx, data.ID, data.TagID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditDirUntag, []types.ObjectId{dir.ID, data.TagID}, nil, nil)
+
+	return nil
+}
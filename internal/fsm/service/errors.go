@@ -1,15 +1,29 @@
 package service
 
 import (
+	"errors"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/libs/ownerrors"
 	"log/slog"
+	"net/http"
 )
 
 func NewWrongUserError(l *slog.Logger, errs ...error) error {
 	return ownerrors.NewValidationError(l, "userID is not equal", "wrong user", errs...)
 }
 
+func NewLockedError(l *slog.Logger, errs ...error) error {
+	return ownerrors.NewError(l, http.StatusLocked, "file is locked", "file is locked by another user", errs...)
+}
+
+func NewPreconditionFailedError(l *slog.Logger, errs ...error) error {
+	return ownerrors.NewError(l, http.StatusPreconditionFailed, "revision mismatch", "the item was changed in the meantime", errs...)
+}
+
 func NewDBError(l *slog.Logger, err error) error {
+	if errors.Is(err, core.ErrRevisionMismatch) {
+		return NewPreconditionFailedError(l, err)
+	}
 	if err != nil {
 		return ownerrors.NewNotFoundError(l, "db error", err.Error(), err)
 	}
@@ -0,0 +1,98 @@
+package file
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"maps"
+)
+
+type AttrsUpdater interface {
+	GetAttrSchemas(ctx context.Context, userID string, directoryIDs []string) ([]core.AttrSchema, error)
+	UpdateAttrs(ctx context.Context, id types.ObjectId, attrs map[string]string) error
+}
+
+type AttrsRequest struct {
+	service.IfMatch
+
+	ID    types.ObjectId    `params:"id" validate:"required"`
+	Set   map[string]string `json:"set" validate:"dive,keys,attrkey,endkeys"`
+	Unset []string          `json:"unset" validate:"dive,attrkey"`
+}
+
+// UpdateAttrs sets and unsets attributes of the file. Unset is applied after Set,
+// the result must satisfy the attribute schema closest to the file.
+func (s *Service) UpdateAttrs(ctx owncontext.Context, data *AttrsRequest) (*core.File, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "UpdateAttrs"))
+
+	file, err := s.getAndCheckUser(ctx, data.ID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string]string, len(file.Attrs)+len(data.Set))
+	maps.Copy(attrs, file.Attrs)
+	maps.Copy(attrs, data.Set)
+	for _, key := range data.Unset {
+		delete(attrs, key)
+	}
+
+	schema, err := s.attrSchema(ctx, file)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	if schema != nil {
+		if err := schema.Validate(s.v, attrs); err != nil {
+			return nil, ownerrors.NewValidationError(l, "attrs do not satisfy schema", err.Error(), err)
+		}
+	}
+
+	if err := s.s.UpdateAttrs(ctx, file.ID, attrs); err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditFileAttrs, []types.ObjectId{file.ID}, core.AuditValues{"attrs": file.Attrs}, core.AuditValues{"attrs": attrs})
+	file.Attrs = attrs
+	file.Revision++
+
+	return file, nil
+}
+
+// attrSchema returns the schema of the nearest directory above the file,
+// falling back to the schema of the user. It returns nil if neither exists.
+func (s *Service) attrSchema(ctx owncontext.Context, file *core.File) (*core.AttrSchema, error) {
+	parent, err := s.s.GetDirectory(ctx, types.ObjectId(file.ParentDirectoryID))
+	if err != nil {
+		return nil, err
+	}
+
+	directoryIDs := make([]string, 0, len(parent.Path)+1)
+	directoryIDs = append(directoryIDs, string(parent.ID))
+	for i := len(parent.Path) - 1; i >= 0; i-- {
+		directoryIDs = append(directoryIDs, string(parent.Path[i].ID))
+	}
+
+	schemas, err := s.s.GetAttrSchemas(ctx, file.UserID, directoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byDirectory := make(map[string]*core.AttrSchema, len(schemas))
+	for i := range schemas {
+		byDirectory[schemas[i].DirectoryID] = &schemas[i]
+	}
+	for _, id := range append(directoryIDs, "") {
+		if schema, ok := byDirectory[id]; ok {
+			return schema, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -28,14 +28,17 @@ type CreateRequest struct {
 }
 
 func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*Response, error) {
-	l := s.l.With(slog.String("op", "Create"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Create"))
 
-	_, err := s.getAndCheckDirectory(ctx, data.ParentDirID)
+	parent, err := s.getAndCheckDirectory(ctx, data.ParentDirID)
 	if err != nil {
 		return nil, err
 	}
+	if err := service.CheckQuota(l, s.a, ctx, parent.UserID, int(data.Size)); err != nil {
+		return nil, err
+	}
 
-	file, err := s.s.Create(ctx, data.ParentDirID, ctx.UserID(), data.Name, data.Extension, data.Size)
+	file, err := s.s.Create(ctx, data.ParentDirID, parent.UserID, data.Name, data.Extension, data.Size)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
@@ -44,6 +47,13 @@ func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*Response
 	if err != nil {
 		return nil, ownerrors.NewInternalError(l, "unable to communicate with FS", err)
 	}
+	s.r.Modified(ctx, file.ID)
+	s.t.Record(ctx, core.AuditFileCreate, []types.ObjectId{file.ID, parent.ID}, nil, core.AuditValues{
+		"name":      file.Name,
+		"extension": file.Extension,
+		"size":      file.Size,
+		"owner":     file.UserID,
+	})
 
 	return &Response{
 		File:         *file,
@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/StratuStore/fsm/internal/libs/ownerrors"
@@ -19,11 +20,13 @@ type Deleter interface {
 }
 
 type DeleteRequest struct {
+	service.IfMatch
+
 	ID string `params:"id" validate:"required"`
 }
 
 func (s *Service) Delete(ctx owncontext.Context, data *DeleteRequest) error {
-	l := s.l.With(slog.String("op", "Delete"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Delete"))
 
 	id, err := types.ObjectIdFromHex(data.ID)
 	if err != nil {
@@ -38,19 +41,41 @@ func (s *Service) Delete(ctx owncontext.Context, data *DeleteRequest) error {
 		id = types.ObjectId(objID.Hex())
 	}
 
+	// the FS deletes files of every tenant
+	if ctx.UserID() == serviceAccountID {
+		ctx = owncontext.Unscoped(ctx)
+	}
+
 	file, err := s.s.Get(ctx, id)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
 
-	if file.UserID != ctx.UserID() && ctx.UserID() != serviceAccountID {
-		return service.NewWrongUserError(l)
+	if ctx.UserID() != serviceAccountID {
+		if err := service.CheckWrite(l, s.a, ctx, file.UserID); err != nil {
+			return err
+		}
+		if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+			return err
+		}
+		if err := s.checkLock(l, ctx, file); err != nil {
+			return err
+		}
+		ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = s.s.Delete(ctx, file.ID)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
+	s.t.Record(ctx, core.AuditFileDelete, []types.ObjectId{file.ID}, core.AuditValues{
+		"name":              file.Name,
+		"parentDirectoryID": file.ParentDirectoryID,
+		"owner":             file.UserID,
+	}, nil)
 
 	if ctx.UserID() != serviceAccountID {
 		go func() {
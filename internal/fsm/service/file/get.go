@@ -8,6 +8,7 @@ import (
 	"github.com/StratuStore/fsm/internal/libs/ownerrors"
 	"github.com/mbretter/go-mongodb/types"
 	"log/slog"
+	"time"
 )
 
 type Getter interface {
@@ -16,25 +17,36 @@ type Getter interface {
 }
 
 type GetRequest struct {
+	service.IfNoneMatch
+
 	ID types.ObjectId `params:"id" validate:"required"`
 }
 
 func (s *Service) Get(ctx owncontext.Context, data *GetRequest) (*Response, error) {
-	l := s.l.With(slog.String("op", "Get"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Get"))
 
 	file, err := s.s.Get(ctx, data.ID)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
 
-	if file.UserID != ctx.UserID() && !file.Public {
-		return nil, service.NewWrongUserError(l)
+	if err := service.CheckRead(l, s.a, ctx, file.UserID, file.Public); err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+		return nil, err
+	}
+	if err := data.CheckRevision(l, file.Revision); err != nil {
+		return nil, err
 	}
 
 	host, connectionID, err := s.c.Open(ctx, file.ID)
 	if err != nil {
 		return nil, ownerrors.NewInternalError(l, "unable to communicate with FS", err)
 	}
+	s.r.Opened(ctx, file.ID)
+	s.t.Record(ctx, core.AuditFileOpen, []types.ObjectId{file.ID}, nil, nil)
+	file.Locks = core.ActiveLocks(file.Locks, time.Now())
 
 	return &Response{
 		File:         *file,
@@ -44,30 +56,36 @@ func (s *Service) Get(ctx owncontext.Context, data *GetRequest) (*Response, erro
 }
 
 func (s *Service) getAndCheckUser(ctx owncontext.Context, id types.ObjectId) (*core.File, error) {
-	l := s.l.With(slog.String("op", "getAndCheckUser"))
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckUser"))
 
 	file, err := s.s.Get(ctx, id)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
 
-	if file.UserID != ctx.UserID() {
-		return nil, service.NewWrongUserError(l)
+	if err := service.CheckWrite(l, s.a, ctx, file.UserID); err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+		return nil, err
 	}
 
 	return file, nil
 }
 
 func (s *Service) getAndCheckDirectory(ctx owncontext.Context, id types.ObjectId) (*core.Directory, error) {
-	l := s.l.With(slog.String("op", "getAndCheckDirectory"))
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckDirectory"))
 
 	dir, err := s.s.GetDirectory(ctx, id)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
 
-	if dir.UserID != ctx.UserID() {
-		return nil, service.NewWrongUserError(l)
+	if err := service.CheckWrite(l, s.a, ctx, dir.UserID); err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, dir.ID); err != nil {
+		return nil, err
 	}
 
 	return dir, nil
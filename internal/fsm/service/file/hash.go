@@ -0,0 +1,76 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"strings"
+)
+
+const DefaultDuplicatesLimit = 100
+
+type Hasher interface {
+	SetHash(ctx context.Context, id types.ObjectId, hash string) error
+	SetMimeType(ctx context.Context, id types.ObjectId, mimeType string) error
+	GetDuplicates(ctx context.Context, userID string, offset, limit uint) ([]core.Duplicates, error)
+}
+
+// Commit stores the content hash and the sniffed MIME type reported by the FS after an upload
+// was committed. It is called by the communicator, not by users.
+func (s *Service) Commit(ctx context.Context, id types.ObjectId, hash, mimeType string) error {
+	l := s.l.With(slog.String("op", "Commit"), slog.Any("id", id))
+	if c, ok := ctx.(owncontext.Context); ok {
+		l = c.Logger(l)
+	}
+
+	hash = strings.ToLower(hash)
+	if !core.ValidHash(hash) {
+		return ownerrors.NewValidationError(l, "invalid hash from FS", "invalid hash", fmt.Errorf("hash %q", hash))
+	}
+
+	// an unknown or malformed type keeps the one derived from the extension
+	mimeType = core.NormalizeMimeType(mimeType)
+	if mimeType == core.DefaultMimeType {
+		mimeType = ""
+	}
+
+	if err := s.s.SetHash(ctx, id, hash); err != nil {
+		return service.NewDBError(l, err)
+	}
+	if mimeType != "" {
+		if err := s.s.SetMimeType(ctx, id, mimeType); err != nil {
+			return service.NewDBError(l, err)
+		}
+	}
+	s.t.Record(ctx, core.AuditFileCommit, []types.ObjectId{id}, nil, core.AuditValues{"hash": hash, "mimeType": mimeType})
+
+	return nil
+}
+
+type DuplicatesRequest struct {
+	Offset uint `query:"offset" validate:"-"`
+	Limit  uint `query:"limit" validate:"-"`
+}
+
+func (s *Service) Duplicates(ctx owncontext.Context, data *DuplicatesRequest) (*[]core.Duplicates, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Duplicates"))
+
+	if err := service.CheckUnrestricted(l, ctx); err != nil {
+		return nil, err
+	}
+	if data.Limit == 0 {
+		data.Limit = DefaultDuplicatesLimit
+	}
+
+	duplicates, err := s.s.GetDuplicates(ctx, ctx.UserID(), data.Offset, data.Limit)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &duplicates, nil
+}
@@ -0,0 +1,131 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultLockTimeout = 30 * time.Minute
+	MaxLockTimeout     = 24 * time.Hour
+)
+
+type Locker interface {
+	Lock(ctx context.Context, id types.ObjectId, lock core.Lock) (*core.File, error)
+	Unlock(ctx context.Context, id types.ObjectId, userID string) error
+}
+
+type LockRequest struct {
+	service.IfMatch
+
+	ID      types.ObjectId `params:"id" validate:"required"`
+	Mode    core.LockMode  `query:"mode" validate:"omitempty,oneof=exclusive shared"`
+	Timeout uint           `query:"timeout" validate:"max=86400"` // seconds
+}
+
+// Lock checks out the file for the user. Taking the lock again extends it.
+// Exclusive locks need write access, shared locks read access.
+func (s *Service) Lock(ctx owncontext.Context, data *LockRequest) (*core.Lock, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Lock"))
+
+	if data.Mode == "" {
+		data.Mode = core.LockExclusive
+	}
+	timeout := DefaultLockTimeout
+	if data.Timeout != 0 {
+		timeout = min(time.Duration(data.Timeout)*time.Second, MaxLockTimeout)
+	}
+
+	file, err := s.s.Get(ctx, data.ID)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	if data.Mode == core.LockExclusive {
+		err = service.CheckWrite(l, s.a, ctx, file.UserID)
+	} else {
+		err = service.CheckRead(l, s.a, ctx, file.UserID, file.Public)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+		return nil, err
+	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := core.Lock{
+		UserID:    ctx.UserID(),
+		Mode:      data.Mode,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(timeout),
+	}
+	if _, err := s.s.Lock(ctx, file.ID, lock); errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, service.NewLockedError(l, err)
+	} else if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditFileLock, []types.ObjectId{file.ID}, nil, core.AuditValues{"mode": lock.Mode, "expiresAt": lock.ExpiresAt})
+
+	return &lock, nil
+}
+
+type UnlockRequest struct {
+	service.IfMatch
+
+	ID    types.ObjectId `params:"id" validate:"required"`
+	Force bool           `query:"force" validate:"-"` // releases the locks of every user, only for the owner of the file or an admin
+}
+
+// Unlock releases the lock of the user. Forced, it releases the locks of every user,
+// which only the user owning the file and admins may do.
+
+func (s *Service) Unlock(ctx owncontext.Context, data *UnlockRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Unlock"))
+
+	file, err := s.s.Get(ctx, data.ID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+		return err
+	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return err
+	}
+
+	userID := ctx.UserID()
+	if data.Force {
+		if file.UserID != ctx.UserID() && !ctx.IsAdmin() {
+			return ownerrors.NewForbiddenError(l, "force unlock by another user", "only the owner of the file or an admin can release the locks of other users")
+		}
+		userID = ""
+	}
+
+	if err := s.s.Unlock(ctx, file.ID, userID); err != nil {
+		return service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditFileUnlock, []types.ObjectId{file.ID}, nil, core.AuditValues{"force": data.Force})
+
+	return nil
+}
+
+// checkLock returns an error if another user holds a lock on the file.
+func (s *Service) checkLock(l *slog.Logger, ctx owncontext.Context, file *core.File) error {
+	if core.LockedFor(file.Locks, ctx.UserID(), time.Now()) {
+		return service.NewLockedError(l)
+	}
+
+	return nil
+}
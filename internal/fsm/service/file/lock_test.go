@@ -0,0 +1,53 @@
+package file
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+// lockStorage records the user whose locks Unlock releases, empty for every user.
+type lockStorage struct {
+	storage
+	unlocked []string
+}
+
+func (s *lockStorage) Unlock(_ context.Context, _ types.ObjectId, userID string) error {
+	s.unlocked = append(s.unlocked, userID)
+
+	return nil
+}
+
+func TestForceUnlock(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		identity owncontext.Identity
+		status   int
+	}{
+		{name: "owner", identity: owncontext.Identity{UserID: "alice"}},
+		{name: "admin", identity: owncontext.Identity{UserID: "carol", Admin: true}},
+		{name: "writer", identity: owncontext.Identity{UserID: "bob"}, status: http.StatusForbidden},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s := &lockStorage{storage: storage{file: &core.File{ID: fileID, UserID: "alice"}}}
+			files := New(slog.New(slog.DiscardHandler), s, nil, nil, nil, access{}, trail{})
+			ctx := owncontext.NewWithIdentity(context.Background(), c.identity)
+
+			require.NoError(t, files.Unlock(ctx, &UnlockRequest{ID: fileID}))
+			err := files.Unlock(ctx, &UnlockRequest{ID: fileID, Force: true})
+			if c.status != 0 {
+				require.Equal(t, c.status, status(err), "%v", err)
+				require.Equal(t, []string{c.identity.UserID}, s.unlocked)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{c.identity.UserID, ""}, s.unlocked)
+			}
+		})
+	}
+}
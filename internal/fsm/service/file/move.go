@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/mbretter/go-mongodb/types"
@@ -13,12 +14,14 @@ type Mover interface {
 }
 
 type MoveRequest struct {
+	service.IfMatch
+
 	ID types.ObjectId `params:"id" validate:"required"`
 	To types.ObjectId `query:"to" validate:"required"`
 }
 
 func (s *Service) Move(ctx owncontext.Context, data *MoveRequest) error {
-	l := s.l.With(slog.String("op", "Move"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Move"))
 
 	to, err := s.getAndCheckDirectory(ctx, data.To)
 	if err != nil {
@@ -28,11 +31,29 @@ func (s *Service) Move(ctx owncontext.Context, data *MoveRequest) error {
 	if err != nil {
 		return err
 	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return err
+	}
+	if err := s.checkLock(l, ctx, file); err != nil {
+		return err
+	}
+
+	if file.UserID != to.UserID {
+		if err := service.CheckQuota(l, s.a, ctx, to.UserID, int(file.Size)); err != nil {
+			return err
+		}
+	}
 
+	// moving between a personal tree and a workspace hands the file over to the new owner
 	err = s.s.Move(ctx, file.ID, to.ID)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
 
+	s.t.Record(ctx, core.AuditFileMove, []types.ObjectId{file.ID},
+		core.AuditValues{"parentDirectoryID": file.ParentDirectoryID, "owner": file.UserID},
+		core.AuditValues{"parentDirectoryID": to.ID, "owner": to.UserID})
+
 	return nil
 }
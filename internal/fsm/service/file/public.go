@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/mbretter/go-mongodb/types"
@@ -13,25 +14,40 @@ type Sharer interface {
 }
 
 type PublicateRequest struct {
+	service.IfMatch
+
 	ID     types.ObjectId `params:"id" validate:"required"`
 	Public bool           `query:"public" validate:"-"`
 }
 
 func (s *Service) Publicate(ctx owncontext.Context, data *PublicateRequest) error {
-	l := s.l.With(slog.String("op", "Publicate"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Publicate"))
 
 	file, err := s.s.Get(ctx, data.ID)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
-	if file.UserID != ctx.UserID() {
-		return service.NewWrongUserError(l)
+	if err := service.CheckWrite(l, s.a, ctx, file.UserID); err != nil {
+		return err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+		return err
+	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return err
+	}
+	if data.Public {
+		if err := service.CheckPublicSharing(l, s.a, ctx); err != nil {
+			return err
+		}
 	}
 
 	err = s.s.Share(ctx, data.ID, data.Public)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
+	s.t.Record(ctx, core.AuditFileShare, []types.ObjectId{file.ID}, core.AuditValues{"public": file.Public}, core.AuditValues{"public": data.Public})
 
 	return nil
 }
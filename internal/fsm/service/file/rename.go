@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/mbretter/go-mongodb/types"
@@ -13,22 +14,32 @@ type Renamer interface {
 }
 
 type RenameRequest struct {
+	service.IfMatch
+
 	ID   types.ObjectId `params:"id" validate:"required"`
 	Name string         `query:"name" validate:"required"`
 }
 
 func (s *Service) Rename(ctx owncontext.Context, data *RenameRequest) error {
-	l := s.l.With(slog.String("op", "Rename"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Rename"))
 
 	file, err := s.getAndCheckUser(ctx, data.ID)
 	if err != nil {
 		return err
 	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return err
+	}
+	if err := s.checkLock(l, ctx, file); err != nil {
+		return err
+	}
 
 	err = s.s.Rename(ctx, file.ID, data.Name)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
+	s.t.Record(ctx, core.AuditFileRename, []types.ObjectId{file.ID}, core.AuditValues{"name": file.Name}, core.AuditValues{"name": data.Name})
 
 	return nil
 }
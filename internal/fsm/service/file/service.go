@@ -2,6 +2,7 @@ package file
 
 import (
 	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/go-playground/validator/v10"
 	"log/slog"
 )
 
@@ -14,18 +15,31 @@ type Storage interface {
 	Updater
 	Starer
 	Sharer
+	Tagger
+	AttrsUpdater
+	Hasher
+	UsageGetter
+	Locker
 }
 
 type Service struct {
 	l *slog.Logger
 	s Storage
 	c service.Communicator
+	v *validator.Validate
+	r service.ActivityRecorder
+	a service.Access
+	t service.AuditTrail
 }
 
-func New(l *slog.Logger, s Storage, c service.Communicator) *Service {
+func New(l *slog.Logger, s Storage, c service.Communicator, v *validator.Validate, r service.ActivityRecorder, a service.Access, t service.AuditTrail) *Service {
 	return &Service{
 		l: l.With("module", "internal.fsm.service.file.Service"),
 		s: s,
 		c: c,
+		v: v,
+		r: r,
+		a: a,
+		t: t,
 	}
 }
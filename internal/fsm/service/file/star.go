@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/mbretter/go-mongodb/types"
@@ -9,24 +10,42 @@ import (
 )
 
 type Starer interface {
-	Star(ctx context.Context, id types.ObjectId) error
+	Star(ctx context.Context, id types.ObjectId, starred bool) error
 }
 
-func (s *Service) Star(ctx owncontext.Context, data *GetRequest) error {
-	l := s.l.With(slog.String("op", "Star"))
+type StarRequest struct {
+	service.IfMatch
+
+	ID types.ObjectId `params:"id" validate:"required"`
+	// Starred is the new state, sent explicitly so that concurrent requests do not undo each other
+	Starred *bool `query:"starred" validate:"required"`
+}
+
+// Star sets the starred state of the file, repeating it with the same state changes nothing.
+func (s *Service) Star(ctx owncontext.Context, data *StarRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Star"))
 
 	file, err := s.s.Get(ctx, data.ID)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
-	if file.UserID != ctx.UserID() {
-		return service.NewWrongUserError(l)
+	if err := service.CheckWrite(l, s.a, ctx, file.UserID); err != nil {
+		return err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+		return err
+	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return err
 	}
 
-	err = s.s.Star(ctx, data.ID)
+	starred := *data.Starred
+	err = s.s.Star(ctx, data.ID, starred)
 	if err != nil {
 		return service.NewDBError(l, err)
 	}
+	s.t.Record(ctx, core.AuditFileStar, []types.ObjectId{file.ID}, core.AuditValues{"starred": file.Starred}, core.AuditValues{"starred": starred})
 
 	return nil
 }
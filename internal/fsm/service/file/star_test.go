@@ -0,0 +1,66 @@
+package file
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+var fileID = types.ObjectId("65f000000000000000000001")
+
+type storage struct {
+	Storage
+	file *core.File
+}
+
+func (s *storage) Get(_ context.Context, _ types.ObjectId) (*core.File, error) {
+	file := *s.file
+
+	return &file, nil
+}
+
+func (s *storage) Star(_ context.Context, _ types.ObjectId, starred bool) error {
+	s.file.Starred = starred
+
+	return nil
+}
+
+// access lets every user change every item.
+type access struct {
+	service.Access
+}
+
+func (access) CanWrite(owncontext.Context, string) (bool, error) {
+	return true, nil
+}
+
+func (access) InSubtree(owncontext.Context, types.ObjectId) (bool, error) {
+	return true, nil
+}
+
+type trail struct{}
+
+func (trail) Record(context.Context, string, []types.ObjectId, core.AuditValues, core.AuditValues) {}
+
+func TestStar(t *testing.T) {
+	s := &storage{file: &core.File{ID: fileID, UserID: "user"}}
+	files := New(slog.New(slog.DiscardHandler), s, nil, nil, nil, access{}, trail{})
+	ctx := owncontext.New(context.Background(), "user")
+
+	// the sent state is set as is, repeating it changes nothing
+	for _, starred := range []bool{true, true, false, false} {
+		require.NoError(t, files.Star(ctx, &StarRequest{ID: fileID, Starred: &starred}))
+		require.Equal(t, starred, s.file.Starred)
+	}
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+	require.Error(t, v.Struct(&StarRequest{ID: fileID}))
+	require.NoError(t, v.Struct(&StarRequest{ID: fileID, Starred: &s.file.Starred}))
+}
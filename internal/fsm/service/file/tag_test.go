@@ -0,0 +1,71 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	aliceTagID = types.ObjectId("65f000000000000000000002")
+	bobTagID   = types.ObjectId("65f000000000000000000003")
+)
+
+// tagStorage attaches the tags of alice and bob to the file.
+type tagStorage struct {
+	storage
+}
+
+func (s *tagStorage) GetTag(_ context.Context, id types.ObjectId) (*core.Tag, error) {
+	owner := "alice"
+	if id == bobTagID {
+		owner = "bob"
+	}
+
+	return &core.Tag{ID: id, UserID: owner, Name: owner}, nil
+}
+
+func (s *tagStorage) AddTag(_ context.Context, _ types.ObjectId, tag core.TagRef) error {
+	s.file.Tags = append(s.file.Tags, tag)
+
+	return nil
+}
+
+func (s *tagStorage) RemoveTag(_ context.Context, _, tagID types.ObjectId) error {
+	s.file.Tags = nil
+
+	return nil
+}
+
+func status(err error) int {
+	var userErr ownerrors.UserError
+	if !errors.As(err, &userErr) {
+		return 0
+	}
+
+	return userErr.Status()
+}
+
+func TestTag(t *testing.T) {
+	s := &tagStorage{storage{file: &core.File{ID: fileID, UserID: "alice"}}}
+	files := New(slog.New(slog.DiscardHandler), s, nil, nil, nil, access{}, trail{})
+	ctx := owncontext.New(context.Background(), "alice")
+
+	err := files.AddTag(ctx, &TagRequest{ID: fileID, TagID: bobTagID})
+	require.Equal(t, http.StatusBadRequest, status(err), "%v", err)
+	require.Empty(t, s.file.Tags)
+
+	require.NoError(t, files.AddTag(ctx, &TagRequest{ID: fileID, TagID: aliceTagID}))
+	require.Equal(t, []core.TagRef{{ID: aliceTagID, Name: "alice"}}, s.file.Tags)
+
+	require.NoError(t, files.RemoveTag(ctx, &TagRequest{ID: fileID, TagID: aliceTagID}))
+	require.Empty(t, s.file.Tags)
+}
@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
 	"github.com/StratuStore/fsm/internal/fsm/service"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/StratuStore/fsm/internal/libs/ownerrors"
@@ -19,19 +20,34 @@ type UpdateResponse struct {
 }
 
 type UpdateRequest struct {
+	service.IfMatch
+
 	ID   types.ObjectId `params:"id" validate:"required"`
 	Size uint           `query:"size" validate:"required"`
 }
 
 func (s *Service) Update(ctx owncontext.Context, data *UpdateRequest) (*UpdateResponse, error) {
-	l := s.l.With(slog.String("op", "Update"))
+	l := ctx.Logger(s.l).With(slog.String("op", "Update"))
 
 	file, err := s.s.Get(ctx, data.ID)
 	if err != nil {
 		return nil, service.NewDBError(l, err)
 	}
-	if file.UserID != ctx.UserID() {
-		return nil, service.NewWrongUserError(l)
+	if err := service.CheckWrite(l, s.a, ctx, file.UserID); err != nil {
+		return nil, err
+	}
+	if err := service.CheckSubtree(l, s.a, ctx, types.ObjectId(file.ParentDirectoryID)); err != nil {
+		return nil, err
+	}
+	ctx, err = data.CheckRevision(l, ctx, file.ID, file.Revision)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLock(l, ctx, file); err != nil {
+		return nil, err
+	}
+	if err := service.CheckQuota(l, s.a, ctx, file.UserID, int(data.Size)-int(file.Size)); err != nil {
+		return nil, err
 	}
 
 	err = s.s.Update(ctx, data.ID, data.Size)
@@ -43,6 +59,8 @@ func (s *Service) Update(ctx owncontext.Context, data *UpdateRequest) (*UpdateRe
 	if err != nil {
 		return nil, ownerrors.NewInternalError(l, "unable to communicate with FS", err)
 	}
+	s.r.Modified(ctx, file.ID)
+	s.t.Record(ctx, core.AuditFileUpdate, []types.ObjectId{file.ID}, core.AuditValues{"size": file.Size}, core.AuditValues{"size": data.Size})
 
 	return &UpdateResponse{
 		Host:         host,
@@ -0,0 +1,52 @@
+package file
+
+import (
+	"cmp"
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+	"slices"
+)
+
+type UsageGetter interface {
+	GetUsage(ctx context.Context, userID string) ([]core.MimeTypeUsage, error)
+}
+
+type UsageRequest struct{}
+
+func (s *Service) Usage(ctx owncontext.Context, _ *UsageRequest) (*core.Usage, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Usage"))
+
+	if err := service.CheckUnrestricted(l, ctx); err != nil {
+		return nil, err
+	}
+	mimeTypes, err := s.s.GetUsage(ctx, ctx.UserID())
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	usage := core.Usage{Categories: []core.CategoryUsage{}}
+	categories := make(map[string]int)
+	for _, mimeType := range mimeTypes {
+		usage.Count += mimeType.Count
+		usage.Size += mimeType.Size
+
+		category := core.MimeCategory(mimeType.MimeType)
+		num, ok := categories[category]
+		if !ok {
+			num = len(usage.Categories)
+			categories[category] = num
+			usage.Categories = append(usage.Categories, core.CategoryUsage{Category: category})
+		}
+		usage.Categories[num].Count += mimeType.Count
+		usage.Categories[num].Size += mimeType.Size
+	}
+
+	slices.SortFunc(usage.Categories, func(a, b core.CategoryUsage) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.Category, b.Category))
+	})
+
+	return &usage, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+// IfMatch is embedded into requests changing a document, see handler.WithHeaders.
+type IfMatch struct {
+	IfMatch string `reqHeader:"If-Match" json:"-" validate:"-"`
+}
+
+func (m *IfMatch) Headers() any {
+	return m
+}
+
+// CheckRevision returns 412 if the If-Match header was sent and does not match the revision of the document id.
+// Otherwise the storage changes the document with the returned context only while it still has the revision,
+// the write of a document changed in between fails with core.ErrRevisionMismatch, see owncontext.WithPrecondition.
+func (m *IfMatch) CheckRevision(l *slog.Logger, ctx owncontext.Context, id types.ObjectId, revision uint64) (owncontext.Context, error) {
+	if m.IfMatch == "" {
+		return ctx, nil
+	}
+	if !core.MatchETag(m.IfMatch, core.ETag(revision)) {
+		return nil, NewPreconditionFailedError(l)
+	}
+
+	return owncontext.WithPrecondition(ctx, string(id), revision), nil
+}
+
+// IfNoneMatch is embedded into requests reading a document, see handler.WithHeaders.
+type IfNoneMatch struct {
+	IfNoneMatch string `reqHeader:"If-None-Match" json:"-" validate:"-"`
+}
+
+func (m *IfNoneMatch) Headers() any {
+	return m
+}
+
+// CheckRevision returns an empty 304 if the If-None-Match header matches the revision.
+func (m *IfNoneMatch) CheckRevision(l *slog.Logger, revision uint64) error {
+	if m.IfNoneMatch == "" || !core.MatchETag(m.IfNoneMatch, core.ETag(revision)) {
+		return nil
+	}
+
+	return ownerrors.NewNotModifiedError(l, core.ETag(revision))
+}
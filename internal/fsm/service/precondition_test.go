@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func status(err error) int {
+	var userErr ownerrors.UserError
+	if !errors.As(err, &userErr) {
+		return 0
+	}
+
+	return userErr.Status()
+}
+
+func TestIfMatch(t *testing.T) {
+	l := slog.New(slog.DiscardHandler)
+	ctx := owncontext.New(context.Background(), "user")
+	id := types.ObjectId("65f000000000000000000001")
+
+	checked, err := (&IfMatch{}).CheckRevision(l, ctx, id, 3)
+	require.NoError(t, err)
+	require.Nil(t, owncontext.PreconditionOf(checked))
+
+	_, err = (&IfMatch{IfMatch: `"2"`}).CheckRevision(l, ctx, id, 3)
+	require.Equal(t, http.StatusPreconditionFailed, status(err))
+
+	checked, err = (&IfMatch{IfMatch: `"3"`}).CheckRevision(l, ctx, id, 3)
+	require.NoError(t, err)
+	require.Equal(t, &owncontext.Precondition{ID: string(id), Revision: 3}, owncontext.PreconditionOf(checked))
+
+	// a conditional write finding the document changed fails like the check itself
+	err = NewDBError(l, fmt.Errorf("unable to rename: %w", core.ErrRevisionMismatch))
+	require.Equal(t, http.StatusPreconditionFailed, status(err))
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	l := slog.New(slog.DiscardHandler)
+
+	require.NoError(t, (&IfNoneMatch{IfNoneMatch: `"2"`}).CheckRevision(l, 3))
+
+	var notModified *ownerrors.NotModifiedError
+	require.ErrorAs(t, (&IfNoneMatch{IfNoneMatch: `W/"3"`}).CheckRevision(l, 3), &notModified)
+	require.Equal(t, `"3"`, notModified.ETag)
+}
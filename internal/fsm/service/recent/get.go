@@ -0,0 +1,31 @@
+package recent
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+)
+
+const DefaultLimit = 50
+
+type ListRequest struct {
+	By     string `query:"by" validate:"omitempty,oneof=opened modified"` // empty for both
+	Offset uint   `query:"offset" validate:"-"`
+	Limit  uint   `query:"limit" validate:"-"`
+}
+
+func (s *Service) List(ctx owncontext.Context, data *ListRequest) (*[]core.RecentFile, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	if data.Limit == 0 {
+		data.Limit = DefaultLimit
+	}
+
+	files, err := s.s.GetRecent(ctx, ctx.UserID(), data.By, data.Offset, data.Limit)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &files, nil
+}
@@ -0,0 +1,122 @@
+package recent
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"time"
+)
+
+const (
+	maxBatchSize = 100
+	writeTimeout = 10 * time.Second
+)
+
+type Writer interface {
+	Record(ctx context.Context, activities []core.Activity, retention time.Duration) error
+}
+
+// Recorder buffers activities and writes them in batches in the background,
+// so recording never delays a request. Activities are dropped when the buffer is full.
+type Recorder struct {
+	l          *slog.Logger
+	w          Writer
+	retention  time.Duration
+	activities chan core.Activity
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func NewRecorder(l *slog.Logger, cfg *config.Config, w Writer) *Recorder {
+	return &Recorder{
+		l:          l.With("module", "internal.fsm.service.recent.Recorder"),
+		w:          w,
+		retention:  cfg.RecentRetention,
+		activities: make(chan core.Activity, cfg.RecentBufferSize),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Opened records that the user of ctx opened the file, in the tenant of ctx.
+func (r *Recorder) Opened(ctx owncontext.Context, fileID types.ObjectId) {
+	r.record(core.Activity{UserID: ctx.UserID(), TenantID: ctx.TenantID(), FileID: fileID, OpenedAt: time.Now()})
+}
+
+// Modified records that the user of ctx modified the file, in the tenant of ctx.
+func (r *Recorder) Modified(ctx owncontext.Context, fileID types.ObjectId) {
+	r.record(core.Activity{UserID: ctx.UserID(), TenantID: ctx.TenantID(), FileID: fileID, ModifiedAt: time.Now()})
+}
+
+func (r *Recorder) record(activity core.Activity) {
+	select {
+	case r.activities <- activity:
+	default:
+		r.l.Warn("activity buffer is full, dropping activity", slog.Any("fileID", activity.FileID))
+	}
+}
+
+func (r *Recorder) Start(_ context.Context) error {
+	go r.run()
+
+	return nil
+}
+
+// Stop writes the buffered activities and waits for the background writer to finish.
+func (r *Recorder) Stop(ctx context.Context) error {
+	close(r.stop)
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	for {
+		select {
+		case activity := <-r.activities:
+			r.write(r.batch(activity))
+		case <-r.stop:
+			for {
+				select {
+				case activity := <-r.activities:
+					r.write(r.batch(activity))
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// batch collects the activities already waiting in the buffer after first.
+func (r *Recorder) batch(first core.Activity) []core.Activity {
+	batch := []core.Activity{first}
+	for len(batch) < maxBatchSize {
+		select {
+		case activity := <-r.activities:
+			batch = append(batch, activity)
+		default:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+func (r *Recorder) write(batch []core.Activity) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := r.w.Record(ctx, batch, r.retention); err != nil {
+		r.l.Error("unable to record activities", slog.Int("count", len(batch)), slog.String("err", err.Error()))
+	}
+}
@@ -0,0 +1,59 @@
+package recent
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+type writerFunc func(activities []core.Activity)
+
+func (f writerFunc) Record(_ context.Context, activities []core.Activity, _ time.Duration) error {
+	f(activities)
+	return nil
+}
+
+func TestRecorderFlushesOnStop(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		recorded []core.Activity
+	)
+	cfg := &config.Config{Recent: config.Recent{RecentBufferSize: 10}}
+	r := NewRecorder(slog.New(slog.DiscardHandler), cfg, writerFunc(func(activities []core.Activity) {
+		mu.Lock()
+		defer mu.Unlock()
+		recorded = append(recorded, activities...)
+	}))
+
+	ctx := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "user", TenantID: "tenant"})
+	for range 5 {
+		r.Opened(ctx, types.ObjectId("65f000000000000000000001"))
+	}
+	r.Modified(ctx, types.ObjectId("65f000000000000000000002"))
+
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Stop(context.Background()))
+
+	require.Len(t, recorded, 6)
+	require.False(t, recorded[5].ModifiedAt.IsZero())
+	require.Equal(t, core.Activity{UserID: "user", TenantID: "tenant", FileID: "65f000000000000000000002", ModifiedAt: recorded[5].ModifiedAt}, recorded[5])
+}
+
+func TestRecorderDropsWhenFull(t *testing.T) {
+	cfg := &config.Config{Recent: config.Recent{RecentBufferSize: 1}}
+	r := NewRecorder(slog.New(slog.DiscardHandler), cfg, writerFunc(func([]core.Activity) {}))
+
+	ctx := owncontext.New(context.Background(), "user")
+	r.Opened(ctx, types.ObjectId("65f000000000000000000001"))
+	r.Opened(ctx, types.ObjectId("65f000000000000000000002"))
+
+	require.Len(t, r.activities, 1)
+}
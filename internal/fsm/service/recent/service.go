@@ -0,0 +1,23 @@
+package recent
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"log/slog"
+)
+
+type Storage interface {
+	GetRecent(ctx context.Context, userID, by string, offset, limit uint) ([]core.RecentFile, error)
+}
+
+type Service struct {
+	l *slog.Logger
+	s Storage
+}
+
+func New(l *slog.Logger, s Storage) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.recent.Service"),
+		s: s,
+	}
+}
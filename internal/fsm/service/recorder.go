@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+)
+
+// ActivityRecorder records file activity of the user of ctx without blocking the caller.
+type ActivityRecorder interface {
+	Opened(ctx owncontext.Context, fileID types.ObjectId)
+	Modified(ctx owncontext.Context, fileID types.ObjectId)
+}
+
+// AuditTrail records actions without blocking the caller. The actor, tenant, client IP and request ID
+// are taken from ctx if it is an owncontext.Context.
+type AuditTrail interface {
+	Record(ctx context.Context, action string, targets []types.ObjectId, before, after core.AuditValues)
+}
@@ -0,0 +1,21 @@
+package schema
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+)
+
+type ListRequest struct{}
+
+func (s *Service) List(ctx owncontext.Context, _ *ListRequest) (*[]core.AttrSchema, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	schemas, err := s.s.List(ctx, ctx.UserID())
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &schemas, nil
+}
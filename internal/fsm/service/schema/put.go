@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type PutRequest struct {
+	DirectoryID types.ObjectId   `json:"directoryID" validate:"-"`
+	Fields      []core.AttrField `json:"fields" validate:"unique=Key,dive"`
+	Strict      bool             `json:"strict" validate:"-"`
+}
+
+// Put replaces the schema of the directory, or the schema of the user when DirectoryID is empty.
+func (s *Service) Put(ctx owncontext.Context, data *PutRequest) (*core.AttrSchema, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Put"))
+
+	if data.DirectoryID != "" {
+		directory, err := s.s.GetDirectory(ctx, data.DirectoryID)
+		if err != nil {
+			return nil, service.NewDBError(l, err)
+		}
+		if directory.UserID != ctx.UserID() {
+			return nil, service.NewWrongUserError(l)
+		}
+	}
+
+	schema := &core.AttrSchema{
+		UserID:      ctx.UserID(),
+		DirectoryID: string(data.DirectoryID),
+		Fields:      data.Fields,
+		Strict:      data.Strict,
+	}
+	if err := schema.CheckRules(s.v); err != nil {
+		return nil, ownerrors.NewValidationError(l, "invalid schema rules", err.Error(), err)
+	}
+
+	schema, err := s.s.Put(ctx, schema)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/go-playground/validator/v10"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type Storage interface {
+	Get(ctx context.Context, id types.ObjectId) (*core.AttrSchema, error)
+	GetDirectory(ctx context.Context, id types.ObjectId) (*core.Directory, error)
+	List(ctx context.Context, userID string) ([]core.AttrSchema, error)
+	Put(ctx context.Context, schema *core.AttrSchema) (*core.AttrSchema, error)
+	Delete(ctx context.Context, id types.ObjectId) error
+}
+
+type Service struct {
+	l *slog.Logger
+	s Storage
+	v *validator.Validate
+}
+
+func New(l *slog.Logger, s Storage, v *validator.Validate) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.schema.Service"),
+		s: s,
+		v: v,
+	}
+}
+
+func (s *Service) getAndCheckOwner(ctx owncontext.Context, id types.ObjectId) (*core.AttrSchema, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckOwner"))
+
+	schema, err := s.s.Get(ctx, id)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	if schema.UserID != ctx.UserID() {
+		return nil, service.NewWrongUserError(l)
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,77 @@
+package shortcut
+
+import (
+	"errors"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type CreateRequest struct {
+	ParentDirID types.ObjectId `json:"parentDirID" validate:"required"`
+	TargetID    types.ObjectId `json:"targetID" validate:"required"`
+	TargetType  string         `json:"targetType" validate:"required,oneof=dir file"`
+	Name        string         `json:"name" validate:"-"` // the name of the target by default
+}
+
+// Create adds a shortcut to a directory of the user pointing at a directory or a file the user can read.
+func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*core.Shortcut, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Create"))
+
+	var directoryIDs, fileIDs []types.ObjectId
+	if data.TargetType == core.DirectoryItem {
+		directoryIDs = append(directoryIDs, data.TargetID)
+	} else {
+		fileIDs = append(fileIDs, data.TargetID)
+	}
+	directoryIDs = append(directoryIDs, data.ParentDirID)
+
+	directories, files, err := s.s.GetShortcutTargets(ctx, directoryIDs, fileIDs)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	parent, ok := directories[data.ParentDirID]
+	if !ok {
+		return nil, service.NewDBError(l, errors.New("parent directory not found"))
+	}
+	if err := service.CheckWrite(l, s.a, ctx, parent.UserID); err != nil {
+		return nil, err
+	}
+
+	shortcut := core.Shortcut{
+		UserID:            parent.UserID,
+		ParentDirectoryID: string(data.ParentDirID),
+		Name:              data.Name,
+		TargetID:          data.TargetID,
+		TargetType:        data.TargetType,
+		Status:            core.ShortcutResolved,
+	}
+
+	var owner, name string
+	var public bool
+	if directory, ok := directories[data.TargetID]; ok && data.TargetType == core.DirectoryItem {
+		owner, name, public = directory.UserID, directory.Name, directory.Public
+		shortcut.Directory = directory
+	} else if file, ok := files[data.TargetID]; ok && data.TargetType == core.FileItem {
+		owner, name, public = file.UserID, file.Name, file.Public
+		shortcut.File = file
+	} else {
+		return nil, ownerrors.NewNotFoundError(l, "shortcut target not found", "target not found")
+	}
+	if err := service.CheckRead(l, s.a, ctx, owner, public); err != nil {
+		return nil, err
+	}
+	if shortcut.Name == "" {
+		shortcut.Name = name
+	}
+
+	if _, err := s.s.Create(ctx, &shortcut); err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &shortcut, nil
+}
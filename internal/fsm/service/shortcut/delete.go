@@ -0,0 +1,27 @@
+package shortcut
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type DeleteRequest struct {
+	ID types.ObjectId `params:"id" validate:"required"`
+}
+
+func (s *Service) Delete(ctx owncontext.Context, data *DeleteRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Delete"))
+
+	shortcut, err := s.getAndCheckOwner(ctx, data.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.s.Delete(ctx, shortcut.ID); err != nil {
+		return service.NewDBError(l, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package shortcut
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type RenameRequest struct {
+	ID   types.ObjectId `params:"id" validate:"required"`
+	Name string         `query:"name" validate:"required"`
+}
+
+func (s *Service) Rename(ctx owncontext.Context, data *RenameRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Rename"))
+
+	shortcut, err := s.getAndCheckOwner(ctx, data.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.s.Rename(ctx, shortcut.ID, data.Name); err != nil {
+		return service.NewDBError(l, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package shortcut
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type Storage interface {
+	Get(ctx context.Context, id types.ObjectId) (*core.Shortcut, error)
+	Create(ctx context.Context, shortcut *core.Shortcut) (*core.Shortcut, error)
+	Rename(ctx context.Context, id types.ObjectId, newName string) error
+	Delete(ctx context.Context, id types.ObjectId) error
+	GetShortcutTargets(
+		ctx context.Context,
+		directoryIDs, fileIDs []types.ObjectId,
+	) (map[types.ObjectId]*core.Directory, map[types.ObjectId]*core.File, error)
+}
+
+type Service struct {
+	l *slog.Logger
+	s Storage
+	a service.Access
+}
+
+func New(l *slog.Logger, s Storage, a service.Access) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.shortcut.Service"),
+		s: s,
+		a: a,
+	}
+}
+
+func (s *Service) getAndCheckOwner(ctx owncontext.Context, id types.ObjectId) (*core.Shortcut, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckOwner"))
+
+	shortcut, err := s.s.Get(ctx, id)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	if err := service.CheckWrite(l, s.a, ctx, shortcut.UserID); err != nil {
+		return nil, err
+	}
+
+	return shortcut, nil
+}
@@ -0,0 +1,38 @@
+package smart
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+)
+
+type CreateRequest struct {
+	Name        string      `json:"name" validate:"required"`
+	Query       string      `json:"query" validate:"-"`
+	Filter      core.Filter `json:"filter"`
+	SortByField string      `json:"sortByField" validate:"omitempty,searchsortfields"`
+	SortOrder   int         `json:"sortOrder" validate:"oneof=-1 0 1"`
+}
+
+func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*core.SavedSearch, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Create"))
+
+	if err := checkQuery(l, data.Query); err != nil {
+		return nil, err
+	}
+
+	search, err := s.s.Create(ctx, &core.SavedSearch{
+		UserID:      ctx.UserID(),
+		Name:        data.Name,
+		Query:       data.Query,
+		Filter:      data.Filter,
+		SortByField: data.SortByField,
+		SortOrder:   data.SortOrder,
+	})
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return search, nil
+}
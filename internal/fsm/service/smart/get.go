@@ -0,0 +1,45 @@
+package smart
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/fsm/service/directory"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type ListRequest struct{}
+
+func (s *Service) List(ctx owncontext.Context, _ *ListRequest) (*[]core.SavedSearch, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	searches, err := s.s.List(ctx, ctx.UserID())
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &searches, nil
+}
+
+type ExecuteRequest struct {
+	ID     types.ObjectId `params:"id" validate:"required"`
+	Offset uint           `query:"offset" validate:"-"`
+	Limit  uint           `query:"limit" validate:"-"`
+}
+
+func (s *Service) Execute(ctx owncontext.Context, data *ExecuteRequest) (*core.DirectoryLike, error) {
+	search, err := s.getAndCheckOwner(ctx, data.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.searcher.Search(ctx, &directory.SearchRequest{
+		Offset:      data.Offset,
+		Limit:       data.Limit,
+		SortByField: search.SortByField,
+		SortOrder:   search.SortOrder,
+		Query:       search.Query,
+		Filter:      search.Filter,
+	})
+}
@@ -0,0 +1,64 @@
+package smart
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/fsm/service/directory"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"time"
+)
+
+type Storage interface {
+	Get(ctx context.Context, id types.ObjectId) (*core.SavedSearch, error)
+	List(ctx context.Context, userID string) ([]core.SavedSearch, error)
+	Create(ctx context.Context, search *core.SavedSearch) (*core.SavedSearch, error)
+	Update(ctx context.Context, search *core.SavedSearch) error
+	Delete(ctx context.Context, id types.ObjectId) error
+}
+
+// Searcher runs saved searches exactly as GET /directory/search does.
+type Searcher interface {
+	Search(ctx owncontext.Context, data *directory.SearchRequest) (*core.DirectoryLike, error)
+}
+
+type Service struct {
+	l        *slog.Logger
+	s        Storage
+	searcher Searcher
+}
+
+func New(l *slog.Logger, s Storage, searcher Searcher) *Service {
+	return &Service{
+		l:        l.With("module", "internal.fsm.service.smart.Service"),
+		s:        s,
+		searcher: searcher,
+	}
+}
+
+func (s *Service) getAndCheckOwner(ctx owncontext.Context, id types.ObjectId) (*core.SavedSearch, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckOwner"))
+
+	search, err := s.s.Get(ctx, id)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	if search.UserID != ctx.UserID() {
+		return nil, service.NewWrongUserError(l)
+	}
+
+	return search, nil
+}
+
+func checkQuery(l *slog.Logger, query string) error {
+	var filter core.Filter
+	if err := filter.ApplyQuery(query, time.Now()); err != nil {
+		return ownerrors.NewValidationError(l, "unable to parse query", err.Error(), err)
+	}
+
+	return nil
+}
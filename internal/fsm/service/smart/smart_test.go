@@ -0,0 +1,105 @@
+package smart
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service/directory"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+var searchID = types.ObjectId("65f000000000000000000001")
+
+type storage struct {
+	Storage
+	searches []core.SavedSearch
+}
+
+func (s *storage) Get(_ context.Context, id types.ObjectId) (*core.SavedSearch, error) {
+	for _, search := range s.searches {
+		if search.ID == id {
+			return &search, nil
+		}
+	}
+
+	return nil, errors.New("not found")
+}
+
+func (s *storage) List(_ context.Context, userID string) ([]core.SavedSearch, error) {
+	searches := []core.SavedSearch{}
+	for _, search := range s.searches {
+		if search.UserID == userID {
+			searches = append(searches, search)
+		}
+	}
+
+	return searches, nil
+}
+
+func (s *storage) Create(_ context.Context, search *core.SavedSearch) (*core.SavedSearch, error) {
+	search.ID = searchID
+	s.searches = append(s.searches, *search)
+
+	return search, nil
+}
+
+type searcher struct {
+	requests []directory.SearchRequest
+}
+
+func (s *searcher) Search(_ owncontext.Context, data *directory.SearchRequest) (*core.DirectoryLike, error) {
+	s.requests = append(s.requests, *data)
+
+	return &core.DirectoryLike{}, nil
+}
+
+func status(err error) int {
+	var userErr ownerrors.UserError
+	if !errors.As(err, &userErr) {
+		return 0
+	}
+
+	return userErr.Status()
+}
+
+func TestSavedSearch(t *testing.T) {
+	s, r := &storage{}, &searcher{}
+	smart := New(slog.New(slog.DiscardHandler), s, r)
+	alice := owncontext.New(context.Background(), "alice")
+
+	_, err := smart.Create(alice, &CreateRequest{Name: "broken", Query: "modified:someday"})
+	require.Equal(t, http.StatusBadRequest, status(err))
+
+	search, err := smart.Create(alice, &CreateRequest{Name: "recent", Query: "ext:pdf modified:-7d", SortByField: "-relevance", SortOrder: 1})
+	require.NoError(t, err)
+	require.Equal(t, "alice", search.UserID)
+
+	searches, err := smart.List(alice, &ListRequest{})
+	require.NoError(t, err)
+	require.Len(t, *searches, 1)
+
+	searches, err = smart.List(owncontext.New(context.Background(), "bob"), &ListRequest{})
+	require.NoError(t, err)
+	require.Empty(t, *searches)
+
+	_, err = smart.Execute(alice, &ExecuteRequest{ID: searchID, Offset: 10, Limit: 5})
+	require.NoError(t, err)
+	require.Equal(t, []directory.SearchRequest{{
+		Offset:      10,
+		Limit:       5,
+		SortByField: "-relevance",
+		SortOrder:   1,
+		Query:       "ext:pdf modified:-7d",
+	}}, r.requests)
+
+	_, err = smart.Execute(owncontext.New(context.Background(), "bob"), &ExecuteRequest{ID: searchID})
+	require.Equal(t, http.StatusBadRequest, status(err))
+	require.Len(t, r.requests, 1, "searches of other users are not run")
+}
@@ -0,0 +1,52 @@
+package smart
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type UpdateRequest struct {
+	ID          types.ObjectId `params:"id" validate:"required"`
+	Name        *string        `json:"name" validate:"omitempty,min=1"`
+	Query       *string        `json:"query" validate:"-"`
+	Filter      *core.Filter   `json:"filter" validate:"omitempty"`
+	SortByField *string        `json:"sortByField" validate:"omitempty,searchsortfields"`
+	SortOrder   *int           `json:"sortOrder" validate:"omitempty,oneof=-1 0 1"`
+}
+
+func (s *Service) Update(ctx owncontext.Context, data *UpdateRequest) (*core.SavedSearch, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Update"))
+
+	search, err := s.getAndCheckOwner(ctx, data.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Name != nil {
+		search.Name = *data.Name
+	}
+	if data.Query != nil {
+		if err := checkQuery(l, *data.Query); err != nil {
+			return nil, err
+		}
+		search.Query = *data.Query
+	}
+	if data.Filter != nil {
+		search.Filter = *data.Filter
+	}
+	if data.SortByField != nil {
+		search.SortByField = *data.SortByField
+	}
+	if data.SortOrder != nil {
+		search.SortOrder = *data.SortOrder
+	}
+
+	if err := s.s.Update(ctx, search); err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return search, nil
+}
@@ -0,0 +1,23 @@
+package tag
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+)
+
+type CreateRequest struct {
+	Name  string `json:"name" validate:"required,max=64"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*core.Tag, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Create"))
+
+	tag, err := s.s.Create(ctx, ctx.UserID(), data.Name, data.Color)
+	if err != nil {
+		return nil, newStorageError(l, err)
+	}
+
+	return tag, nil
+}
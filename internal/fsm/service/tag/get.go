@@ -0,0 +1,21 @@
+package tag
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+)
+
+type ListRequest struct{}
+
+func (s *Service) List(ctx owncontext.Context, _ *ListRequest) (*[]core.Tag, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	tags, err := s.s.List(ctx, ctx.UserID())
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &tags, nil
+}
@@ -0,0 +1,55 @@
+package tag
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log/slog"
+)
+
+type Storage interface {
+	GetTag(ctx context.Context, id types.ObjectId) (*core.Tag, error)
+	List(ctx context.Context, userID string) ([]core.Tag, error)
+	Create(ctx context.Context, userID, name, color string) (*core.Tag, error)
+	Update(ctx context.Context, id types.ObjectId, name, color string) error
+	Delete(ctx context.Context, id types.ObjectId) error
+}
+
+type Service struct {
+	l *slog.Logger
+	s Storage
+}
+
+func New(l *slog.Logger, s Storage) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.tag.Service"),
+		s: s,
+	}
+}
+
+func (s *Service) getAndCheckOwner(ctx owncontext.Context, id types.ObjectId) (*core.Tag, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckOwner"))
+
+	tag, err := s.s.GetTag(ctx, id)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	if tag.UserID != ctx.UserID() {
+		return nil, service.NewWrongUserError(l)
+	}
+
+	return tag, nil
+}
+
+func newStorageError(l *slog.Logger, err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return ownerrors.NewConflictError(l, "duplicate tag name", "tag with this name already exists", err)
+	}
+
+	return service.NewDBError(l, err)
+}
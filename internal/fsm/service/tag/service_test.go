@@ -0,0 +1,123 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var tagID = types.ObjectId("65f000000000000000000001")
+
+// storage holds a single tag of alice, Delete detaches it from the items in tagged.
+type storage struct {
+	Storage
+	tag    core.Tag
+	tagged map[types.ObjectId]bool
+	calls  []string
+}
+
+func (s *storage) GetTag(_ context.Context, id types.ObjectId) (*core.Tag, error) {
+	if id != s.tag.ID {
+		return nil, mongo.ErrNoDocuments
+	}
+	tag := s.tag
+
+	return &tag, nil
+}
+
+func (s *storage) Create(_ context.Context, userID, name, color string) (*core.Tag, error) {
+	if name == s.tag.Name && userID == s.tag.UserID {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}
+	}
+	s.calls = append(s.calls, "Create "+userID)
+
+	return &core.Tag{ID: tagID, UserID: userID, Name: name, Color: color}, nil
+}
+
+func (s *storage) Update(_ context.Context, id types.ObjectId, name, _ string) error {
+	s.calls = append(s.calls, "Update "+name)
+
+	return nil
+}
+
+func (s *storage) Delete(_ context.Context, id types.ObjectId) error {
+	s.calls = append(s.calls, "Delete")
+	clear(s.tagged)
+
+	return nil
+}
+
+func status(err error) int {
+	var userErr ownerrors.UserError
+	if !errors.As(err, &userErr) {
+		return 0
+	}
+
+	return userErr.Status()
+}
+
+func newService() (*Service, *storage) {
+	s := &storage{
+		tag:    core.Tag{ID: tagID, UserID: "alice", Name: "work"},
+		tagged: map[types.ObjectId]bool{"65f000000000000000000002": true},
+	}
+
+	return New(slog.New(slog.DiscardHandler), s), s
+}
+
+func TestCreate(t *testing.T) {
+	tags, s := newService()
+
+	tag, err := tags.Create(owncontext.New(context.Background(), "bob"), &CreateRequest{Name: "work"})
+	require.NoError(t, err)
+	require.Equal(t, "bob", tag.UserID)
+
+	_, err = tags.Create(owncontext.New(context.Background(), "alice"), &CreateRequest{Name: "work"})
+	require.Equal(t, http.StatusConflict, status(err), "%v", err)
+	require.Equal(t, []string{"Create bob"}, s.calls)
+}
+
+func TestOwnership(t *testing.T) {
+	name := "private"
+
+	for _, c := range []struct {
+		name   string
+		user   string
+		status int
+		calls  []string
+	}{
+		{name: "owner", user: "alice", calls: []string{"Update private", "Delete"}},
+		{name: "another user", user: "bob", status: http.StatusBadRequest},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			tags, s := newService()
+			ctx := owncontext.New(context.Background(), c.user)
+
+			_, updateErr := tags.Update(ctx, &UpdateRequest{ID: tagID, Name: &name})
+			deleteErr := tags.Delete(ctx, &DeleteRequest{ID: tagID})
+			for _, err := range []error{updateErr, deleteErr} {
+				if c.status != 0 {
+					require.Equal(t, c.status, status(err), "%v", err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+			require.Equal(t, c.calls, s.calls)
+			// only deleting the tag detaches it from the items
+			require.Equal(t, c.status != 0, len(s.tagged) == 1)
+		})
+	}
+
+	tags, _ := newService()
+	err := tags.Delete(owncontext.New(context.Background(), "alice"), &DeleteRequest{ID: "65f000000000000000000009"})
+	require.Equal(t, http.StatusNotFound, status(err), "%v", err)
+}
@@ -0,0 +1,38 @@
+package tag
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"time"
+)
+
+type UpdateRequest struct {
+	ID    types.ObjectId `params:"id" validate:"required"`
+	Name  *string        `json:"name" validate:"omitempty,min=1,max=64"`
+	Color *string        `json:"color" validate:"omitempty,hexcolor"`
+}
+
+func (s *Service) Update(ctx owncontext.Context, data *UpdateRequest) (*core.Tag, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Update"))
+
+	tag, err := s.getAndCheckOwner(ctx, data.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Name != nil {
+		tag.Name = *data.Name
+	}
+	if data.Color != nil {
+		tag.Color = *data.Color
+	}
+
+	if err := s.s.Update(ctx, tag.ID, tag.Name, tag.Color); err != nil {
+		return nil, newStorageError(l, err)
+	}
+	tag.UpdatedAt = time.Now()
+
+	return tag, nil
+}
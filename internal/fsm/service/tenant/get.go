@@ -0,0 +1,59 @@
+package tenant
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"log/slog"
+)
+
+type CurrentRequest struct{}
+
+// Current returns the configuration of the tenant of the user, it is readable by every member.
+func (s *Service) Current(ctx owncontext.Context, _ *CurrentRequest) (*core.Tenant, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Current"))
+
+	tenant, err := s.s.GetTenant(ctx, ctx.TenantID())
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return tenant, nil
+}
+
+type ListRequest struct{}
+
+func (s *Service) List(ctx owncontext.Context, _ *ListRequest) (*[]core.Tenant, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	if !ctx.IsAdmin() || ctx.TenantID() != "" {
+		return nil, ownerrors.NewForbiddenError(l, "not an admin of the default tenant", "forbidden")
+	}
+
+	tenants, err := s.s.List(ctx)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &tenants, nil
+}
+
+type GetRequest struct {
+	ID string `params:"id" validate:"required"`
+}
+
+func (s *Service) Get(ctx owncontext.Context, data *GetRequest) (*core.Tenant, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Get"))
+
+	if err := s.checkAdmin(ctx, data.ID); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.s.GetTenant(ctx, data.ID)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return tenant, nil
+}
@@ -0,0 +1,57 @@
+package tenant
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+)
+
+type PutRequest struct {
+	ID            string `params:"id" validate:"required"`
+	Name          string `json:"name" validate:"max=128"`
+	UserQuota     uint   `json:"userQuota" validate:"-"`
+	PublicSharing bool   `json:"publicSharing" validate:"-"`
+	Workspaces    bool   `json:"workspaces" validate:"-"`
+}
+
+// Put creates or replaces the configuration of the tenant.
+func (s *Service) Put(ctx owncontext.Context, data *PutRequest) (*core.Tenant, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Put"))
+
+	if err := s.checkAdmin(ctx, data.ID); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.s.Put(ctx, &core.Tenant{
+		ID:            data.ID,
+		Name:          data.Name,
+		UserQuota:     data.UserQuota,
+		PublicSharing: data.PublicSharing,
+		Workspaces:    data.Workspaces,
+	})
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return tenant, nil
+}
+
+type DeleteRequest struct {
+	ID string `params:"id" validate:"required"`
+}
+
+// Delete resets the tenant to the default configuration.
+func (s *Service) Delete(ctx owncontext.Context, data *DeleteRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Delete"))
+
+	if err := s.checkAdmin(ctx, data.ID); err != nil {
+		return err
+	}
+
+	if err := s.s.Delete(ctx, data.ID); err != nil {
+		return service.NewDBError(l, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package tenant
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"log/slog"
+)
+
+type Storage interface {
+	GetTenant(ctx context.Context, id string) (*core.Tenant, error)
+	List(ctx context.Context) ([]core.Tenant, error)
+	Put(ctx context.Context, tenant *core.Tenant) (*core.Tenant, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type Service struct {
+	l *slog.Logger
+	s Storage
+}
+
+func New(l *slog.Logger, s Storage) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.tenant.Service"),
+		s: s,
+	}
+}
+
+// checkAdmin allows admins of the default tenant to manage every tenant, admins of other tenants only their own.
+func (s *Service) checkAdmin(ctx owncontext.Context, id string) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "checkAdmin"))
+
+	if !ctx.IsAdmin() || (ctx.TenantID() != "" && ctx.TenantID() != id) {
+		return ownerrors.NewForbiddenError(l, "not an admin of the tenant", "forbidden")
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package token
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const (
+	MaxLifetime = 365 * 24 * time.Hour
+	// hintLength is the length of the start of the secret kept as core.APIToken.Hint
+	hintLength = len(core.APITokenPrefix) + 6
+)
+
+type CreateRequest struct {
+	Name   string   `json:"name" validate:"required,max=128"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=files:read files:write"`
+	// DirectoryID limits the token to the subtree of the directory
+	DirectoryID types.ObjectId `json:"directoryID" validate:"-"`
+	ExpiresAt   time.Time      `json:"expiresAt" validate:"required"`
+}
+
+type CreateResponse struct {
+	core.APIToken
+	// Secret is the token itself, it can not be read again
+	Secret string `json:"secret"`
+}
+
+// Create issues a token of the user limited to the scopes and optionally to a directory the user can access.
+// The scopes have to be granted to the token of the request as well, so a token can not issue a more powerful one.
+func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*CreateResponse, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Create"))
+
+	for _, scope := range data.Scopes {
+		if !slices.Contains(ctx.Scopes(), scope) {
+			return nil, ownerrors.NewForbiddenError(l, "scope not granted", "the scope "+scope+" is not granted to the token of the request")
+		}
+	}
+
+	now := time.Now()
+	if !data.ExpiresAt.After(now) || data.ExpiresAt.Sub(now) > MaxLifetime {
+		return nil, ownerrors.NewValidationError(l, "wrong expiry", "the token has to expire within a year")
+	}
+
+	if !data.DirectoryID.IsZero() {
+		dir, err := s.s.GetDirectory(ctx, data.DirectoryID)
+		if err != nil {
+			return nil, service.NewDBError(l, err)
+		}
+		if slices.Contains(data.Scopes, handler.ScopeFilesWrite) {
+			err = service.CheckWrite(l, s.a, ctx, dir.UserID)
+		} else {
+			err = service.CheckRead(l, s.a, ctx, dir.UserID, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	secret, err := core.NewAPITokenSecret()
+	if err != nil {
+		return nil, ownerrors.NewInternalError(l, "unable to generate secret", err)
+	}
+
+	slices.Sort(data.Scopes)
+	token, err := s.s.Create(ctx, &core.APIToken{
+		UserID:      ctx.UserID(),
+		Name:        data.Name,
+		Hash:        core.HashAPIToken(secret),
+		Hint:        secret[:hintLength],
+		Scopes:      slices.Compact(data.Scopes),
+		DirectoryID: data.DirectoryID,
+		ExpiresAt:   data.ExpiresAt,
+	})
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditTokenCreate, []types.ObjectId{token.ID}, nil, core.AuditValues{
+		"name": token.Name, "scopes": token.Scopes, "directoryID": token.DirectoryID, "expiresAt": token.ExpiresAt,
+	})
+
+	return &CreateResponse{
+		APIToken: *token,
+		Secret:   secret,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/handler"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+type storage struct {
+	Storage
+}
+
+func (storage) Create(_ context.Context, token *core.APIToken) (*core.APIToken, error) {
+	token.ID = types.ObjectId("65f000000000000000000001")
+
+	return token, nil
+}
+
+type trail struct{}
+
+func (trail) Record(context.Context, string, []types.ObjectId, core.AuditValues, core.AuditValues) {}
+
+func status(err error) int {
+	var userErr ownerrors.UserError
+	if !errors.As(err, &userErr) {
+		return 0
+	}
+
+	return userErr.Status()
+}
+
+func TestCreateKeepsScopes(t *testing.T) {
+	tokens := New(slog.New(slog.DiscardHandler), storage{}, nil, trail{})
+	readOnly := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{
+		UserID: "user",
+		Scopes: []string{handler.ScopeFilesRead},
+	})
+	request := func(scopes ...string) *CreateRequest {
+		return &CreateRequest{Name: "ci", Scopes: scopes, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+
+	_, err := tokens.Create(readOnly, request(handler.ScopeFilesRead, handler.ScopeFilesWrite))
+	require.Equal(t, http.StatusForbidden, status(err), "%v", err)
+
+	created, err := tokens.Create(readOnly, request(handler.ScopeFilesRead))
+	require.NoError(t, err)
+	require.Equal(t, []string{handler.ScopeFilesRead}, created.Scopes)
+}
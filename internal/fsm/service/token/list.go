@@ -0,0 +1,40 @@
+package token
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type ListRequest struct{}
+
+// List returns the tokens of the user without their secrets.
+func (s *Service) List(ctx owncontext.Context, _ *ListRequest) (*[]core.APIToken, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	tokens, err := s.s.List(ctx, ctx.UserID())
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &tokens, nil
+}
+
+type RevokeRequest struct {
+	ID types.ObjectId `params:"id" validate:"required"`
+}
+
+// Revoke deletes the token, it is rejected from then on.
+func (s *Service) Revoke(ctx owncontext.Context, data *RevokeRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "Revoke"))
+
+	token, err := s.s.Delete(ctx, ctx.UserID(), data.ID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+	s.t.Record(ctx, core.AuditTokenRevoke, []types.ObjectId{token.ID}, core.AuditValues{"name": token.Name}, nil)
+
+	return nil
+}
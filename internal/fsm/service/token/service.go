@@ -0,0 +1,33 @@
+package token
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type Storage interface {
+	Create(ctx context.Context, token *core.APIToken) (*core.APIToken, error)
+	List(ctx context.Context, userID string) ([]core.APIToken, error)
+	Delete(ctx context.Context, userID string, id types.ObjectId) (*core.APIToken, error)
+	GetDirectory(ctx context.Context, id types.ObjectId) (*core.Directory, error)
+}
+
+// Service manages the API tokens of users, see core.APIToken. The tokens are accepted by auth.Authenticator.
+type Service struct {
+	l *slog.Logger
+	s Storage
+	a service.Access
+	t service.AuditTrail
+}
+
+func New(l *slog.Logger, s Storage, a service.Access, t service.AuditTrail) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.token.Service"),
+		s: s,
+		a: a,
+		t: t,
+	}
+}
@@ -0,0 +1,33 @@
+package workspace
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"log/slog"
+)
+
+type CreateRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=128"`
+	Quota uint   `json:"quota" validate:"-"`
+}
+
+// Create creates the workspace with its root directory, the user becomes its owner.
+func (s *Service) Create(ctx owncontext.Context, data *CreateRequest) (*core.Workspace, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Create"))
+
+	if err := service.CheckWorkspaces(l, s.a, ctx); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.s.Create(ctx, &core.Workspace{
+		Name:    data.Name,
+		Quota:   data.Quota,
+		Members: []core.Member{{UserID: ctx.UserID(), Role: core.RoleOwner}},
+	})
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return workspace, nil
+}
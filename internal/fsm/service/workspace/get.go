@@ -0,0 +1,31 @@
+package workspace
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type ListRequest struct{}
+
+// List returns the workspaces the user is a member of.
+func (s *Service) List(ctx owncontext.Context, _ *ListRequest) (*[]core.Workspace, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "List"))
+
+	workspaces, err := s.s.List(ctx, ctx.UserID())
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	return &workspaces, nil
+}
+
+type GetRequest struct {
+	ID types.ObjectId `params:"id" validate:"required"`
+}
+
+func (s *Service) Get(ctx owncontext.Context, data *GetRequest) (*core.Workspace, error) {
+	return s.getAndCheckRole(ctx, data.ID)
+}
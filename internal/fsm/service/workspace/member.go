@@ -0,0 +1,64 @@
+package workspace
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/StratuStore/fsm/internal/libs/ownerrors"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type SetMemberRequest struct {
+	ID     types.ObjectId `params:"id" validate:"required"`
+	UserID string         `params:"userID" validate:"required"`
+	Role   core.Role      `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+// SetMember adds the user to the workspace or changes the role of an existing member.
+func (s *Service) SetMember(ctx owncontext.Context, data *SetMemberRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "SetMember"))
+
+	workspace, err := s.getAndCheckRole(ctx, data.ID, core.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if data.Role != core.RoleOwner && workspace.IsLastOwner(data.UserID) {
+		return ownerrors.NewConflictError(l, "last owner", "workspace must keep at least one owner")
+	}
+
+	err = s.s.SetMember(ctx, workspace.ID, core.Member{UserID: data.UserID, Role: data.Role})
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+
+	return nil
+}
+
+type RemoveMemberRequest struct {
+	ID     types.ObjectId `params:"id" validate:"required"`
+	UserID string         `params:"userID" validate:"required"`
+}
+
+// RemoveMember removes the user from the workspace. Owners remove anyone, other members only themselves.
+func (s *Service) RemoveMember(ctx owncontext.Context, data *RemoveMemberRequest) error {
+	l := ctx.Logger(s.l).With(slog.String("op", "RemoveMember"))
+
+	workspace, err := s.getAndCheckRole(ctx, data.ID)
+	if err != nil {
+		return err
+	}
+	if role, _ := workspace.Role(ctx.UserID()); role != core.RoleOwner && data.UserID != ctx.UserID() {
+		return service.NewWrongUserError(l)
+	}
+	if workspace.IsLastOwner(data.UserID) {
+		return ownerrors.NewConflictError(l, "last owner", "workspace must keep at least one owner")
+	}
+
+	err = s.s.RemoveMember(ctx, workspace.ID, data.UserID)
+	if err != nil {
+		return service.NewDBError(l, err)
+	}
+
+	return nil
+}
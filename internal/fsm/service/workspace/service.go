@@ -0,0 +1,58 @@
+package workspace
+
+import (
+	"context"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+)
+
+type Storage interface {
+	GetWorkspace(ctx context.Context, id types.ObjectId) (*core.Workspace, error)
+	List(ctx context.Context, userID string) ([]core.Workspace, error)
+	Create(ctx context.Context, workspace *core.Workspace) (*core.Workspace, error)
+	Update(ctx context.Context, id types.ObjectId, name string, quota uint) error
+	SetMember(ctx context.Context, id types.ObjectId, member core.Member) error
+	RemoveMember(ctx context.Context, id types.ObjectId, userID string) error
+}
+
+type Service struct {
+	l *slog.Logger
+	s Storage
+	a service.Access
+}
+
+func New(l *slog.Logger, s Storage, a service.Access) *Service {
+	return &Service{
+		l: l.With("module", "internal.fsm.service.workspace.Service"),
+		s: s,
+		a: a,
+	}
+}
+
+// getAndCheckRole returns the workspace if the user is a member with one of the roles, any role if none are given.
+func (s *Service) getAndCheckRole(ctx owncontext.Context, id types.ObjectId, roles ...core.Role) (*core.Workspace, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "getAndCheckRole"))
+
+	workspace, err := s.s.GetWorkspace(ctx, id)
+	if err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+
+	role, ok := workspace.Role(ctx.UserID())
+	if !ok {
+		return nil, service.NewWrongUserError(l)
+	}
+	if len(roles) == 0 {
+		return workspace, nil
+	}
+	for _, r := range roles {
+		if r == role {
+			return workspace, nil
+		}
+	}
+
+	return nil, service.NewWrongUserError(l)
+}
@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/service"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"log/slog"
+	"time"
+)
+
+type UpdateRequest struct {
+	ID    types.ObjectId `params:"id" validate:"required"`
+	Name  *string        `json:"name" validate:"omitempty,min=1,max=128"`
+	Quota *uint          `json:"quota" validate:"-"`
+}
+
+func (s *Service) Update(ctx owncontext.Context, data *UpdateRequest) (*core.Workspace, error) {
+	l := ctx.Logger(s.l).With(slog.String("op", "Update"))
+
+	workspace, err := s.getAndCheckRole(ctx, data.ID, core.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Name != nil {
+		workspace.Name = *data.Name
+	}
+	if data.Quota != nil {
+		workspace.Quota = *data.Quota
+	}
+
+	if err := s.s.Update(ctx, workspace.ID, workspace.Name, workspace.Quota); err != nil {
+		return nil, service.NewDBError(l, err)
+	}
+	workspace.UpdatedAt = time.Now()
+
+	return workspace, nil
+}
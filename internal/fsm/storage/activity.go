@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/search"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const ActivityCollection = "activities"
+
+const (
+	openedActivity   = "openedAt"
+	modifiedActivity = "modifiedAt"
+	// lastActivity is the latest of openedActivity and modifiedActivity
+	lastActivity = "lastAt"
+)
+
+// activityFields maps the kinds of activity accepted by GetRecent to the fields storing them.
+var activityFields = map[string]string{
+	"":         lastActivity,
+	"opened":   openedActivity,
+	"modified": modifiedActivity,
+}
+
+type ActivityStorage struct {
+	Storage
+}
+
+func NewActivityStorage(s *Storage) *ActivityStorage {
+	return &ActivityStorage{*s}
+}
+
+// Record stores the activities, each one expires retention after it happened.
+// They are written in the background without a request, so each one carries its tenant,
+// which is added to the upserts here as collection does not scope bulk writes.
+func (s *ActivityStorage) Record(ctx context.Context, activities []core.Activity, retention time.Duration) error {
+	ctx, end := s.observe(ctx, "ActivityStorage.Record")
+	defer end()
+
+	models := make([]mongo.WriteModel, len(activities))
+	for num, activity := range activities {
+		at := activity.OpenedAt
+		fields := bson.D{}
+		if !activity.OpenedAt.IsZero() {
+			fields = append(fields, bson.E{openedActivity, activity.OpenedAt})
+		}
+		if !activity.ModifiedAt.IsZero() {
+			fields = append(fields, bson.E{modifiedActivity, activity.ModifiedAt})
+			at = activity.ModifiedAt
+		}
+		fields = append(fields, bson.E{lastActivity, at}, bson.E{"expiresAt", at.Add(retention)})
+
+		models[num] = mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{"userID", activity.UserID}, {"fileID", activity.FileID}, {tenantField, activity.TenantID}}).
+			SetUpdate(bson.D{{"$max", fields}}).
+			SetUpsert(true)
+	}
+
+	_, err := s.db.Collection(ActivityCollection).
+		BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("unable to record activities: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecent returns the files the user opened or modified, the latest first.
+// by is "opened", "modified" or empty for either of them.
+// Files deleted since or no longer readable by the user, neither own, public nor in a workspace of the user, are skipped.
+func (s *ActivityStorage) GetRecent(ctx context.Context, userID, by string, offset, limit uint) ([]core.RecentFile, error) {
+	ctx, end := s.observe(ctx, "ActivityStorage.GetRecent")
+	defer end()
+
+	field, ok := activityFields[by]
+	if !ok {
+		return nil, fmt.Errorf("unknown activity %q", by)
+	}
+
+	owners, err := NewWorkspaceStorage(&s.Storage).owners(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"userID", userID}, {field, bson.D{{"$exists", true}}}}}},
+		{{"$sort", bson.D{{field, -1}, {"_id", 1}}}},
+		{{"$lookup", bson.D{
+			{"from", FileCollection},
+			{"localField", "fileID"},
+			{"foreignField", "_id"},
+			{"pipeline", bson.A{
+				bson.D{{"$match", bson.D{{"$or", bson.A{bson.D{{"userID", bson.D{{"$in", owners}}}}, bson.D{{"public", true}}}}}}},
+				bson.D{{"$project", bson.D{{search.KeywordsField, 0}}}},
+			}},
+			{"as", "file"},
+		}}},
+		{{"$unwind", "$file"}},
+		{{"$skip", offset}},
+		{{"$limit", limit}},
+	}
+
+	cursor, err := s.collection(ctx, ActivityCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to aggregate recent files: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	files := []core.RecentFile{}
+	if err := cursor.All(ctx, &files); err != nil {
+		return nil, fmt.Errorf("unable to decode recent files: %w", err)
+	}
+
+	return files, nil
+}
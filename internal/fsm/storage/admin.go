@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+)
+
+// userCollections hold the documents owned by a user, see AdminStorage.DeleteUserData.
+var userCollections = []string{
+	FileCollection,
+	DirectoryCollection,
+	ShortcutCollection,
+	TagCollection,
+	AttrSchemaCollection,
+	SavedSearchCollection,
+	ActivityCollection,
+	APITokenCollection,
+}
+
+// AdminStorage works on the trees of every user, the directory operations are the ones of DirectoryStorage.
+type AdminStorage struct {
+	DirectoryStorage
+}
+
+func NewAdminStorage(s *Storage) *AdminStorage {
+	return &AdminStorage{DirectoryStorage{*s}}
+}
+
+// ListUsers returns the users having a root directory, the ones storing the most first. Workspaces are not listed.
+func (s *AdminStorage) ListUsers(ctx context.Context, offset, limit uint) ([]core.UserUsage, error) {
+	ctx, end := s.observe(ctx, "AdminStorage.ListUsers")
+	defer end()
+
+	notWorkspace := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(core.WorkspaceOwnerPrefix)}
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"path", nil}, {"userID", bson.D{{"$not", notWorkspace}}}}}},
+		{{"$sort", bson.D{{"size", -1}, {"_id", 1}}}},
+		{{"$skip", int64(offset)}},
+		{{"$limit", int64(limit)}},
+		countOf(FileCollection, "files"),
+		countOf(DirectoryCollection, "directories"),
+		{{"$project", bson.D{
+			{"userID", 1},
+			{"tenantID", 1},
+			{"size", 1},
+			{"files", bson.D{{"$ifNull", bson.A{bson.D{{"$first", "$files.count"}}, 0}}}},
+			{"directories", bson.D{{"$ifNull", bson.A{bson.D{{"$first", "$directories.count"}}, 0}}}},
+		}}},
+	}
+	cursor, err := s.collection(ctx, DirectoryCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to aggregate users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	users := []core.UserUsage{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("unable to decode users: %w", err)
+	}
+
+	return users, nil
+}
+
+// countOf counts the documents of the user in collection into the field as, only the ones of the tenant of the user.
+func countOf(collection, as string) bson.D {
+	return bson.D{{"$lookup", bson.D{
+		{"from", collection},
+		{"localField", "userID"},
+		{"foreignField", "userID"},
+		{"let", bson.D{{"tenantID", "$" + tenantField}}},
+		{"pipeline", bson.A{
+			bson.D{{"$match", bson.D{{"$expr", bson.D{{"$eq", bson.A{"$" + tenantField, "$$tenantID"}}}}}}},
+			bson.D{{"$count", "count"}},
+		}},
+		{"as", as},
+	}}}
+}
+
+func (s *AdminStorage) GetFile(ctx context.Context, id types.ObjectId) (*core.File, error) {
+	return NewFileStorage(&s.Storage).Get(ctx, id)
+}
+
+func (s *AdminStorage) MoveFile(ctx context.Context, id, toID types.ObjectId) error {
+	return NewFileStorage(&s.Storage).Move(ctx, id, toID)
+}
+
+func (s *AdminStorage) ListWorkspaces(ctx context.Context, userID string) ([]core.Workspace, error) {
+	return NewWorkspaceStorage(&s.Storage).List(ctx, userID)
+}
+
+// GetFileIDs returns the IDs of the files of the user.
+func (s *AdminStorage) GetFileIDs(ctx context.Context, userID string) ([]types.ObjectId, error) {
+	ctx, end := s.observe(ctx, "AdminStorage.GetFileIDs")
+	defer end()
+
+	filter := bson.D{{"userID", userID}}
+	cursor, err := s.collection(ctx, FileCollection).
+		Find(ctx, filter, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find files: %w", err)
+	}
+	var files []core.File
+	if err := cursor.All(ctx, &files); err != nil {
+		return nil, fmt.Errorf("unable to decode files: %w", err)
+	}
+
+	ids := make([]types.ObjectId, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+
+	return ids, nil
+}
+
+// DeleteUserData removes every document of the user and the user from the members of workspaces.
+// The content of the files is left to the caller, see GetFileIDs.
+func (s *AdminStorage) DeleteUserData(ctx context.Context, userID string) error {
+	ctx, end := s.observe(ctx, "AdminStorage.DeleteUserData")
+	defer end()
+
+	filter := bson.D{{"userID", userID}}
+	for _, name := range userCollections {
+		if _, err := s.collection(ctx, name).DeleteMany(ctx, filter); err != nil {
+			return fmt.Errorf("unable to delete from %v: %w", name, err)
+		}
+	}
+
+	update := bson.D{{"$pull", bson.D{{"members", filter}}}}
+	_, err := s.collection(ctx, WorkspaceCollection).
+		UpdateMany(
+			ctx,
+			bson.D{{"members.userID", userID}},
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to remove user from workspaces: %w", err)
+	}
+
+	return nil
+}
+
+// RecalculateSizes sets the size of every directory of the user, and of its embedded copy, to the sum of the files below it.
+func (s *AdminStorage) RecalculateSizes(ctx context.Context, userID string) (*core.Recalculation, error) {
+	ctx, end := s.observe(ctx, "AdminStorage.RecalculateSizes")
+	defer end()
+
+	filter := bson.D{{"userID", userID}}
+	projection := bson.D{{"path", 1}, {"size", 1}, {"directories._id", 1}, {"directories.size", 1}}
+	cursor, err := s.collection(ctx, DirectoryCollection).
+		Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find directories: %w", err)
+	}
+	var dirs []core.Directory
+	if err := cursor.All(ctx, &dirs); err != nil {
+		return nil, fmt.Errorf("unable to decode directories: %w", err)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+		{{"$group", bson.D{{"_id", "$parentDirectoryID"}, {"size", bson.D{{"$sum", "$size"}}}}}},
+	}
+	cursor, err = s.collection(ctx, FileCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sum file sizes: %w", err)
+	}
+	var sums []struct {
+		ParentDirectoryID string `bson:"_id"`
+		Size              uint   `bson:"size"`
+	}
+	if err := cursor.All(ctx, &sums); err != nil {
+		return nil, fmt.Errorf("unable to decode file sizes: %w", err)
+	}
+
+	byID := make(map[types.ObjectId]*core.Directory, len(dirs))
+	for i := range dirs {
+		byID[dirs[i].ID] = &dirs[i]
+	}
+	sizes := make(map[types.ObjectId]uint, len(dirs))
+	for _, sum := range sums {
+		dir, ok := byID[types.ObjectId(sum.ParentDirectoryID)]
+		if !ok {
+			continue
+		}
+		sizes[dir.ID] += sum.Size
+		for _, element := range dir.Path {
+			sizes[element.ID] += sum.Size
+		}
+	}
+
+	result := &core.Recalculation{Directories: uint(len(dirs))}
+	corrected := make(map[types.ObjectId]struct{})
+	for _, dir := range dirs {
+		size := sizes[dir.ID]
+		if dir.Path == nil {
+			result.Size += size
+		}
+		if dir.Size != size {
+			update := bson.D{{"$set", bson.D{{"size", size}}}}
+			if _, err := s.collection(ctx, DirectoryCollection).UpdateOne(ctx, bson.D{{"_id", dir.ID}}, update); err != nil {
+				return nil, fmt.Errorf("unable to update directory size: %w", err)
+			}
+			corrected[dir.ID] = struct{}{}
+		}
+
+		for _, child := range dir.Directories {
+			if _, ok := byID[child.ID]; !ok || child.Size == sizes[child.ID] {
+				continue
+			}
+			filter := bson.D{{"_id", dir.ID}, {"directories._id", child.ID}}
+			update := bson.D{{"$set", bson.D{{"directories.$.size", sizes[child.ID]}}}}
+			if _, err := s.collection(ctx, DirectoryCollection).UpdateOne(ctx, filter, update); err != nil {
+				return nil, fmt.Errorf("unable to update embedded directory size: %w", err)
+			}
+			corrected[child.ID] = struct{}{}
+		}
+	}
+	result.Corrected = uint(len(corrected))
+
+	return result, nil
+}
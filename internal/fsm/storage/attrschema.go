@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const AttrSchemaCollection = "attrSchemas"
+
+type AttrSchemaStorage struct {
+	Storage
+}
+
+func NewAttrSchemaStorage(s *Storage) *AttrSchemaStorage {
+	return &AttrSchemaStorage{*s}
+}
+
+func (s *AttrSchemaStorage) Get(ctx context.Context, id types.ObjectId) (*core.AttrSchema, error) {
+	ctx, end := s.observe(ctx, "AttrSchemaStorage.Get")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+
+	var schema core.AttrSchema
+	err := s.collection(ctx, AttrSchemaCollection).
+		FindOne(ctx, filter).
+		Decode(&schema)
+
+	return &schema, err
+}
+
+func (s *AttrSchemaStorage) List(ctx context.Context, userID string) ([]core.AttrSchema, error) {
+	ctx, end := s.observe(ctx, "AttrSchemaStorage.List")
+	defer end()
+
+	return s.attrSchemas(ctx, userID, nil)
+}
+
+// Put replaces the schema of the directory, or the schema of the user when DirectoryID is empty.
+func (s *AttrSchemaStorage) Put(ctx context.Context, schema *core.AttrSchema) (*core.AttrSchema, error) {
+	ctx, end := s.observe(ctx, "AttrSchemaStorage.Put")
+	defer end()
+
+	timestamp := time.Now()
+
+	filter := bson.D{{"userID", schema.UserID}, {"directoryID", schema.DirectoryID}}
+	update := bson.D{
+		{"$set", bson.D{
+			{"fields", schema.Fields},
+			{"strict", schema.Strict},
+			{"updatedAt", timestamp},
+		}},
+		{"$setOnInsert", bson.D{{tenantField, tenantOf(ctx)}, {"createdAt", timestamp}}},
+	}
+
+	var result core.AttrSchema
+	err := s.collection(ctx, AttrSchemaCollection).
+		FindOneAndUpdate(
+			ctx,
+			filter,
+			update,
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).
+		Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("unable to put attribute schema: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (s *AttrSchemaStorage) Delete(ctx context.Context, id types.ObjectId) error {
+	ctx, end := s.observe(ctx, "AttrSchemaStorage.Delete")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+	_, err := s.collection(ctx, AttrSchemaCollection).DeleteOne(ctx, filter)
+
+	return err
+}
+
+// GetAttrSchemas returns the schema of the user and the schemas of the given directories.
+// All schemas of the user are returned when directoryIDs is nil.
+func (s *Storage) GetAttrSchemas(ctx context.Context, userID string, directoryIDs []string) ([]core.AttrSchema, error) {
+	ctx, end := s.observe(ctx, "Storage.GetAttrSchemas")
+	defer end()
+
+	return s.attrSchemas(ctx, userID, directoryIDs)
+}
+
+// attrSchemas is GetAttrSchemas for storage methods, which are timed themselves.
+func (s *Storage) attrSchemas(ctx context.Context, userID string, directoryIDs []string) ([]core.AttrSchema, error) {
+	filter := bson.D{{"userID", userID}}
+	if directoryIDs != nil {
+		filter = append(filter, bson.E{"directoryID", bson.D{{"$in", append(directoryIDs, "")}}})
+	}
+
+	cursor, err := s.collection(ctx, AttrSchemaCollection).
+		Find(ctx, filter, options.Find().SetSort(bson.D{{"directoryID", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find attribute schemas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	schemas := []core.AttrSchema{}
+	if err := cursor.All(ctx, &schemas); err != nil {
+		return nil, fmt.Errorf("unable to decode attribute schemas: %w", err)
+	}
+
+	return schemas, nil
+}
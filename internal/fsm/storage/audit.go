@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditCollection is append-only, AuditStorage offers no way to change or remove records.
+const AuditCollection = "audit"
+
+type AuditStorage struct {
+	Storage
+}
+
+func NewAuditStorage(s *Storage) *AuditStorage {
+	return &AuditStorage{*s}
+}
+
+// Append stores the records, each of them carries its tenant.
+func (s *AuditStorage) Append(ctx context.Context, records []core.AuditRecord) error {
+	ctx, end := s.observe(ctx, "AuditStorage.Append")
+	defer end()
+
+	db := s.db
+
+	documents := make([]any, len(records))
+	for num := range records {
+		documents[num] = records[num]
+	}
+
+	_, err := db.Collection(AuditCollection).
+		InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("unable to append audit records: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the records matching query, the latest first.
+func (s *AuditStorage) List(ctx context.Context, query core.AuditQuery, offset, limit uint) ([]core.AuditRecord, error) {
+	ctx, end := s.observe(ctx, "AuditStorage.List")
+	defer end()
+
+	opts := options.Find().
+		SetSort(bson.D{{"createdAt", -1}, {"_id", -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := s.collection(ctx, AuditCollection).Find(ctx, auditFilter(query), opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find audit records: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	records := []core.AuditRecord{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("unable to decode audit records: %w", err)
+	}
+
+	return records, nil
+}
+
+// Export calls f for each record matching query, the oldest first, without loading them all at once.
+func (s *AuditStorage) Export(ctx context.Context, query core.AuditQuery, f func(record *core.AuditRecord) error) error {
+	ctx, end := s.observe(ctx, "AuditStorage.Export")
+	defer end()
+
+	opts := options.Find().SetSort(bson.D{{"createdAt", 1}, {"_id", 1}})
+
+	cursor, err := s.collection(ctx, AuditCollection).Find(ctx, auditFilter(query), opts)
+	if err != nil {
+		return fmt.Errorf("unable to find audit records: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var record core.AuditRecord
+		if err := cursor.Decode(&record); err != nil {
+			return fmt.Errorf("unable to decode audit record: %w", err)
+		}
+		if err := f(&record); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func auditFilter(query core.AuditQuery) bson.D {
+	filter := bson.D{}
+	if query.Actor != "" {
+		filter = append(filter, bson.E{"actor", query.Actor})
+	}
+	if !query.Target.IsZero() {
+		filter = append(filter, bson.E{"targets", query.Target})
+	}
+	if query.Action != "" {
+		filter = append(filter, bson.E{"action", query.Action})
+	}
+
+	createdAt := bson.D{}
+	if !query.From.IsZero() {
+		createdAt = append(createdAt, bson.E{"$gte", query.From})
+	}
+	if !query.To.IsZero() {
+		createdAt = append(createdAt, bson.E{"$lt", query.To})
+	}
+	if len(createdAt) > 0 {
+		filter = append(filter, bson.E{"createdAt", createdAt})
+	}
+
+	return filter
+}
@@ -2,11 +2,14 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/search"
 	"github.com/mbretter/go-mongodb/types"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"slices"
 	"time"
@@ -23,59 +26,59 @@ func NewDirectoryStorage(s *Storage) *DirectoryStorage {
 }
 
 func (s *DirectoryStorage) Get(ctx context.Context, id types.ObjectId) (*core.Directory, error) {
-	db := s.db
-
-	filter := bson.D{{"_id", id}}
-
-	var directory core.Directory
-	err := db.Collection(DirectoryCollection).
-		FindOne(ctx, filter).
-		Decode(&directory)
+	ctx, end := s.observe(ctx, "DirectoryStorage.Get")
+	defer end()
 
-	return &directory, err
+	return s.getDirectory(ctx, id)
 }
 
 func (s *DirectoryStorage) GetWithPagination(
 	ctx context.Context,
 	id types.ObjectId,
 	offset, limit uint,
-	sortByField string,
-	sortOrder int,
-) (*core.Directory, error) {
+	sort core.Sort,
+) (*core.DirectoryPage, error) {
+	ctx, end := s.observe(ctx, "DirectoryStorage.GetWithPagination")
+	defer end()
+
 	filter := bson.D{{"_id", id}}
 
-	return s.WithPagination(ctx, filter, offset, limit, sortByField, sortOrder)
+	return s.withPagination(ctx, filter, offset, limit, sort)
 }
 
 func (s *DirectoryStorage) GetRoot(
 	ctx context.Context,
 	userID string,
 	offset, limit uint,
-	sortByField string,
-	sortOrder int,
-) (*core.Directory, error) {
+	sort core.Sort,
+) (*core.DirectoryPage, error) {
+	ctx, end := s.observe(ctx, "DirectoryStorage.GetRoot")
+	defer end()
+
 	filter := bson.D{{"userID", userID}, {"path", nil}}
 
-	if err := s.db.Collection(DirectoryCollection).FindOne(ctx, filter).Err(); err != nil {
+	if err := s.collection(ctx, DirectoryCollection).FindOne(ctx, filter).Err(); err != nil {
 		return nil, err
 	}
 
-	return s.WithPagination(ctx, filter, offset, limit, sortByField, sortOrder)
+	return s.withPagination(ctx, filter, offset, limit, sort)
 }
 
 func (s *DirectoryStorage) CreateRoot(
 	ctx context.Context,
 	userID string,
 	offset, limit uint,
-	sortByField string,
-	sortOrder int,
-) (*core.Directory, error) {
-	db := s.db
+	sort core.Sort,
+) (*core.DirectoryPage, error) {
+	ctx, end := s.observe(ctx, "DirectoryStorage.CreateRoot")
+	defer end()
 
 	directory := core.Directory{
 		UserID:           userID,
+		TenantID:         tenantOf(ctx),
 		Path:             nil,
 		Name:             "root",
+		Keywords:         s.index.Keywords("root", nil),
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
 		Public:           false,
@@ -86,7 +89,7 @@ func (s *DirectoryStorage) CreateRoot(
 		Size:             0,
 	}
 
-	result, err := db.Collection(DirectoryCollection).
+	result, err := s.collection(ctx, DirectoryCollection).
 		InsertOne(ctx, directory)
 	if err != nil {
 		return nil, fmt.Errorf("unable to insert root folder: %w", err)
@@ -101,13 +104,14 @@ func (s *DirectoryStorage) CreateRoot(
 
 	filter := bson.D{{"userID", userID}, {"path", nil}}
 
-	return s.WithPagination(ctx, filter, offset, limit, sortByField, sortOrder)
+	return s.withPagination(ctx, filter, offset, limit, sort)
 }
 
 func (s *DirectoryStorage) Create(ctx context.Context, parentDirID types.ObjectId, userID string, name string) (*core.Directory, error) {
-	db := s.db
+	ctx, end := s.observe(ctx, "DirectoryStorage.Create")
+	defer end()
 
-	parentDir, err := s.Get(ctx, parentDirID)
+	parentDir, err := s.getDirectory(ctx, parentDirID)
 	if err != nil {
 		return nil, fmt.Errorf("unable to find parentDir: %w", err)
 	}
@@ -116,9 +120,11 @@ func (s *DirectoryStorage) Create(ctx context.Context, parentDirID types.ObjectI
 
 	directory := core.Directory{
 		UserID:            userID,
+		TenantID:          tenantOf(ctx),
 		Path:              path,
 		ParentDirectoryID: string(parentDirID),
 		Name:              name,
+		Keywords:          s.index.Keywords(name, nil),
 		CreatedAt:         time.Now(),
 		UpdatedAt:         time.Now(),
 		Public:            false,
@@ -129,11 +135,12 @@ func (s *DirectoryStorage) Create(ctx context.Context, parentDirID types.ObjectI
 		Size:              0,
 	}
 
-	result, err := db.Collection(DirectoryCollection).
+	result, err := s.collection(ctx, DirectoryCollection).
 		InsertOne(ctx, directory)
 	if err != nil {
 		return nil, fmt.Errorf("unable to insert root folder: %w", err)
 	}
+	s.m.Created("directory")
 
 	id, ok := result.InsertedID.(primitive.ObjectID)
 	if !ok {
@@ -141,35 +148,48 @@ func (s *DirectoryStorage) Create(ctx context.Context, parentDirID types.ObjectI
 	}
 	directory.ID = types.ObjectId(id.Hex())
 
-	directory.Directories = nil
-	directory.Files = nil
+	embedded := directory
+	embedded.Directories = nil
+	embedded.Files = nil
+	embedded.Keywords = nil
 	filter := bson.D{{"_id", parentDirID}}
-	update := bson.D{{"$push", bson.D{{"directories", directory}}}, {"$inc", bson.D{{"directoriesCount", 1}}}}
-	_, err = db.Collection(DirectoryCollection).
+	update := bson.D{{"$push", bson.D{{"directories", embedded}}}, {"$inc", bson.D{{"directoriesCount", 1}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
 			update,
 		)
-	directory.Directories = []core.Directory{}
-	directory.Files = []core.File{}
 
 	return &directory, err
 }
 
 func (s *DirectoryStorage) Delete(ctx context.Context, id types.ObjectId) error {
+	ctx, end := s.observe(ctx, "DirectoryStorage.Delete")
+	defer end()
+
 	db := s.db
 
-	dir, err := s.Get(ctx, id)
+	dir, err := s.getDirectory(ctx, id)
 	if err != nil {
 		return fmt.Errorf("unable to find dir: %w", err)
 	}
 
-	filter := bson.D{{"_id", types.ObjectId(dir.ParentDirectoryID)}}
+	// the directory goes first, the write conditional on its revision must fail before anything else changed
+	filter := bson.D{{"_id", id}}
+	result, err := s.collection(ctx, DirectoryCollection).DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("unable to delete dir: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("unable to delete dir: %w", mongo.ErrNoDocuments)
+	}
+
+	filter = bson.D{{"_id", types.ObjectId(dir.ParentDirectoryID)}}
 	update := bson.D{
 		{"$pull", bson.D{{"directories", bson.D{{"_id", id}}}}},
 		{"$inc", bson.D{{"directoriesCount", -1}}}}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
@@ -179,50 +199,44 @@ func (s *DirectoryStorage) Delete(ctx context.Context, id types.ObjectId) error
 		return fmt.Errorf("unable to delete dir from parent: %w", err)
 	}
 
-	if err := IncrementSizes(db, ctx, dir.Path, -int(dir.Size)); err != nil {
-		return err
-	}
-
-	return s.StupidDelete(ctx, id)
+	return IncrementSizes(db, ctx, dir.Path, -int(dir.Size))
 }
 
 func (s *DirectoryStorage) StupidDelete(ctx context.Context, id types.ObjectId) error {
-	db := s.db
+	ctx, end := s.observe(ctx, "DirectoryStorage.StupidDelete")
+	defer end()
 
 	filter := bson.D{{"_id", id}}
-	_, err := db.Collection(DirectoryCollection).DeleteOne(ctx, filter)
+	_, err := s.collection(ctx, DirectoryCollection).DeleteOne(ctx, filter)
 
 	return err
 }
 
 func (s *DirectoryStorage) StupidDeleteFile(ctx context.Context, id types.ObjectId) error {
-	db := s.db
+	ctx, end := s.observe(ctx, "DirectoryStorage.StupidDeleteFile")
+	defer end()
 
 	filter := bson.D{{"_id", id}}
-	_, err := db.Collection(FileCollection).DeleteOne(ctx, filter)
+	_, err := s.collection(ctx, FileCollection).DeleteOne(ctx, filter)
 
 	return err
 }
 
 func (s *DirectoryStorage) Rename(ctx context.Context, id types.ObjectId, newName string) error {
-	db := s.db
+	ctx, end := s.observe(ctx, "DirectoryStorage.Rename")
+	defer end()
+
 	timestamp := time.Now()
 
-	filter := bson.D{{"_id", id}}
-	update := bson.D{{"$set", bson.D{{"name", newName}, {"updatedAt", timestamp}}}}
-	_, err := db.Collection(DirectoryCollection).
-		UpdateOne(
-			ctx,
-			filter,
-			update,
-		)
+	update := bson.D{{"$set", bson.D{{"name", newName}, {"updatedAt", timestamp}, {search.KeywordsField, s.index.Keywords(newName, nil)}}}}
+	revision, err := s.updateRevised(ctx, DirectoryCollection, id, update)
 	if err != nil {
 		return fmt.Errorf("unable to update name of the directory: %w", err)
 	}
 
-	filter = bson.D{{"directories._id", id}}
-	update = bson.D{{"$set", bson.D{{"directories.$.name", newName}, {"directories.$.updatedAt", timestamp}}}}
-	_, err = db.Collection(DirectoryCollection).
+	filter := bson.D{{"directories._id", id}}
+	update = bson.D{{"$set", bson.D{{"directories.$.name", newName}, {"directories.$.updatedAt", timestamp}, {"directories.$.revision", revision}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -234,19 +248,23 @@ func (s *DirectoryStorage) Rename(ctx context.Context, id types.ObjectId, newNam
 
 	filter = bson.D{{"path._id", id}}
 	update = bson.D{{"$set", bson.D{{"path.$.name", newName}}}}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
 			update,
 		)
+	if err != nil {
+		return fmt.Errorf("unable to update path of subdirectories: %w", err)
+	}
 
+	filter = bson.D{{"directories.path._id", id}}
 	arrayFilter := []any{bson.D{{"num._id", id}}}
 	update = bson.D{{"$set", bson.D{{"directories.$[].path.$[num].name", newName}}}}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
-			bson.D{},
+			filter,
 			update,
 			options.Update().SetArrayFilters(options.ArrayFilters{Filters: arrayFilter}),
 		)
@@ -254,30 +272,83 @@ func (s *DirectoryStorage) Rename(ctx context.Context, id types.ObjectId, newNam
 	return err
 }
 
+// Move moves the directory into toID. If toID belongs to another user or workspace, the subtree is handed over to
+// them, when that fails the directory is moved back, so it never stays in the tree of another owner.
 func (s *DirectoryStorage) Move(ctx context.Context, id, toID types.ObjectId) error {
+	ctx, end := s.observe(ctx, "DirectoryStorage.Move")
+	defer end()
+
+	dir, err := s.getDirectory(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find dir: %w", err)
+	}
+	toDir, err := s.getDirectory(ctx, toID)
+	if err != nil {
+		return fmt.Errorf("unable to find target dir: %w", err)
+	}
+
+	if err := s.move(ctx, id, toID); err != nil {
+		return err
+	}
+	if dir.UserID == toDir.UserID {
+		return nil
+	}
+
+	if err := s.setOwner(ctx, id, toDir.UserID); err != nil {
+		rollbackErr := errors.Join(
+			s.setOwner(ctx, id, dir.UserID),
+			s.move(ctx, id, types.ObjectId(dir.ParentDirectoryID)),
+		)
+		if rollbackErr != nil {
+			return fmt.Errorf("unable to set owner: %w, unable to move dir back: %w", err, rollbackErr)
+		}
+
+		return fmt.Errorf("unable to set owner: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DirectoryStorage) move(ctx context.Context, id, toID types.ObjectId) error {
 	db := s.db
 	timestamp := time.Now()
 
-	dir, err := s.Get(ctx, id)
+	dir, err := s.getDirectory(ctx, id)
 	if err != nil {
 		return fmt.Errorf("unable to find dir: %w", err)
 	}
-	dir.UpdatedAt = timestamp
-	fromDir, err := s.Get(ctx, types.ObjectId(dir.ParentDirectoryID))
+	fromDir, err := s.getDirectory(ctx, types.ObjectId(dir.ParentDirectoryID))
 	if err != nil {
 		return fmt.Errorf("unable to find initial dir: %w", err)
 	}
-	toDir, err := s.Get(ctx, toID)
+	toDir, err := s.getDirectory(ctx, toID)
 	if err != nil {
 		return fmt.Errorf("unable to find target dir: %w", err)
 	}
+	path := slices.Clone(toDir.Path)
+	path = append(path, core.PathElement{toDir.ID, toDir.Name})
+
+	// the directory goes first, the write conditional on its revision must fail before anything else changed
+	filter := bson.D{{"_id", id}}
+	update := bson.D{{"$set", bson.D{{"parentDirectoryID", string(toID)}, {"path", path}, {"updatedAt", timestamp}}}}
+	var moved core.Directory
+	err = s.collection(ctx, DirectoryCollection).
+		FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&moved)
+	if err != nil {
+		return fmt.Errorf("unable to update dir parentID: %w", err)
+	}
+	moved.Directories = nil
+	moved.Files = nil
+	moved.Shortcuts = nil
+	moved.Keywords = nil
 
-	filter := bson.D{{"_id", types.ObjectId(dir.ParentDirectoryID)}}
-	update := bson.D{
+	filter = bson.D{{"_id", types.ObjectId(dir.ParentDirectoryID)}}
+	update = bson.D{
 		{"$pull", bson.D{{"directories", bson.D{{"_id", id}}}}},
 		{"$inc", bson.D{{"directoriesCount", -1}, {"size", -int(dir.Size)}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
@@ -290,32 +361,12 @@ func (s *DirectoryStorage) Move(ctx context.Context, id, toID types.ObjectId) er
 		return err
 	}
 
-	path := slices.Clone(toDir.Path)
-	path = append(path, core.PathElement{toDir.ID, toDir.Name})
-
-	filter = bson.D{{"_id", id}}
-	update = bson.D{{"$set", bson.D{{"parentDirectoryID", string(toID)}, {"path", path}, {"updatedAt", timestamp}}}}
-	_, err = db.Collection(DirectoryCollection).
-		UpdateMany(
-			ctx,
-			filter,
-			update,
-		)
-	if err != nil {
-		return fmt.Errorf("unable to update dir parentID: %w", err)
-	}
-
-	oldPath := slices.Clone(dir.Path)
-	dir.Directories = nil
-	dir.Files = nil
-	dir.Path = path
-	dir.ParentDirectoryID = string(toID)
 	filter = bson.D{{"_id", toID}}
 	update = bson.D{
-		{"$push", bson.D{{"directories", dir}}},
+		{"$push", bson.D{{"directories", moved}}},
 		{"$inc", bson.D{{"directoriesCount", 1}, {"size", dir.Size}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
@@ -328,7 +379,7 @@ func (s *DirectoryStorage) Move(ctx context.Context, id, toID types.ObjectId) er
 		return err
 	}
 
-	if err := s.UpdatePath(ctx, id, oldPath, dir.Path); err != nil {
+	if err := s.updatePath(ctx, id, dir.Path, path); err != nil {
 		return err
 	}
 
@@ -342,38 +393,32 @@ func (s *DirectoryStorage) Move(ctx context.Context, id, toID types.ObjectId) er
 	return err
 }
 
-func (s *DirectoryStorage) Star(ctx context.Context, id types.ObjectId) error {
-	directory, err := s.Get(ctx, id)
-	if err != nil {
-		return fmt.Errorf("unable to find directory: %w", err)
-	}
+func (s *DirectoryStorage) Star(ctx context.Context, id types.ObjectId, starred bool) error {
+	ctx, end := s.observe(ctx, "DirectoryStorage.Star")
+	defer end()
 
-	return s.UpdateField(ctx, id, "starred", !directory.Starred)
+	return s.updateField(ctx, id, "starred", starred)
 }
 
 func (s *DirectoryStorage) Share(ctx context.Context, id types.ObjectId, mode bool) error {
-	return s.UpdateField(ctx, id, "public", mode)
+	ctx, end := s.observe(ctx, "DirectoryStorage.Share")
+	defer end()
+
+	return s.updateField(ctx, id, "public", mode)
 }
 
-func (s *DirectoryStorage) UpdateField(ctx context.Context, id types.ObjectId, field string, value any) error {
-	db := s.db
+func (s *DirectoryStorage) updateField(ctx context.Context, id types.ObjectId, field string, value any) error {
 	timestamp := time.Now()
 
-	filter := bson.D{{"_id", id}}
 	update := bson.D{{"$set", bson.D{{field, value}, {"updatedAt", timestamp}}}}
-	_, err := db.Collection(DirectoryCollection).
-		UpdateMany(
-			ctx,
-			filter,
-			update,
-		)
+	revision, err := s.updateRevised(ctx, DirectoryCollection, id, update)
 	if err != nil {
 		return fmt.Errorf("unable to update file field: %w", err)
 	}
 
-	filter = bson.D{{"directories._id", id}}
-	update = bson.D{{"$set", bson.D{{"directories.$." + field, value}, {"directories.$.updatedAt", timestamp}}}}
-	_, err = db.Collection(DirectoryCollection).
+	filter := bson.D{{"directories._id", id}}
+	update = bson.D{{"$set", bson.D{{"directories.$." + field, value}, {"directories.$.updatedAt", timestamp}, {"directories.$.revision", revision}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -386,9 +431,7 @@ func (s *DirectoryStorage) UpdateField(ctx context.Context, id types.ObjectId, f
 	return err
 }
 
-func (s *DirectoryStorage) UpdatePath(ctx context.Context, id types.ObjectId, oldPath []core.PathElement, newPath []core.PathElement) error {
-	db := s.db
-
+func (s *DirectoryStorage) updatePath(ctx context.Context, id types.ObjectId, oldPath []core.PathElement, newPath []core.PathElement) error {
 	oldPathIDs := make([]types.ObjectId, len(oldPath))
 	for num, p := range oldPath {
 		oldPathIDs[num] = p.ID
@@ -398,7 +441,7 @@ func (s *DirectoryStorage) UpdatePath(ctx context.Context, id types.ObjectId, ol
 	update := bson.D{
 		{"$pull", bson.D{{"path", bson.D{{"_id", bson.D{{"$in", oldPathIDs}}}}}}},
 	}
-	_, err := db.Collection(DirectoryCollection).
+	_, err := s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -410,7 +453,7 @@ func (s *DirectoryStorage) UpdatePath(ctx context.Context, id types.ObjectId, ol
 	update = bson.D{
 		{"$push", bson.D{{"path", bson.D{{"$each", newPath}, {"$position", 0}}}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -421,11 +464,11 @@ func (s *DirectoryStorage) UpdatePath(ctx context.Context, id types.ObjectId, ol
 	}
 
 	arrayFilter := []any{bson.D{{"num.path", bson.D{{"$elemMatch", bson.D{{"_id", id}}}}}}}
-	filter = bson.D{}
+	filter = bson.D{{"directories.path._id", id}}
 	update = bson.D{
 		{"$pull", bson.D{{"directories.$[num].path", bson.D{{"_id", bson.D{{"$in", oldPathIDs}}}}}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -438,7 +481,7 @@ func (s *DirectoryStorage) UpdatePath(ctx context.Context, id types.ObjectId, ol
 	update = bson.D{
 		{"$push", bson.D{{"directories.$[num].path", bson.D{{"$each", newPath}, {"$position", 0}}}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -451,3 +494,174 @@ func (s *DirectoryStorage) UpdatePath(ctx context.Context, id types.ObjectId, ol
 
 	return nil
 }
+
+// setOwner hands the directory and everything below it, shortcuts included, over to another user or workspace,
+// see core.WorkspaceOwner.
+func (s *DirectoryStorage) setOwner(ctx context.Context, id types.ObjectId, owner string) error {
+	ids, err := s.subtreeIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	parentIDs := make([]string, len(ids))
+	for i, id := range ids {
+		parentIDs[i] = string(id)
+	}
+
+	filter := bson.D{{"_id", bson.D{{"$in", ids}}}}
+	update := bson.D{{"$set", bson.D{
+		{"userID", owner},
+		{"directories.$[].userID", owner},
+		{"files.$[].userID", owner},
+	}}}
+	_, err = s.collection(ctx, DirectoryCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update subtree owner: %w", err)
+	}
+
+	filter = bson.D{{"parentDirectoryID", bson.D{{"$in", parentIDs}}}}
+	update = bson.D{{"$set", bson.D{{"userID", owner}}}}
+	_, err = s.collection(ctx, FileCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update files owner: %w", err)
+	}
+
+	_, err = s.collection(ctx, ShortcutCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update shortcuts owner: %w", err)
+	}
+
+	filter = bson.D{{"directories._id", id}}
+	update = bson.D{{"$set", bson.D{{"directories.$.userID", owner}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update embedded owner: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubtreeIDs returns the IDs of the directory and every directory below it.
+func (s *DirectoryStorage) GetSubtreeIDs(ctx context.Context, id types.ObjectId) ([]types.ObjectId, error) {
+	ctx, end := s.observe(ctx, "DirectoryStorage.GetSubtreeIDs")
+	defer end()
+
+	return s.subtreeIDs(ctx, id)
+}
+
+// subtreeIDs is GetSubtreeIDs for storage methods, which are timed themselves.
+func (s *DirectoryStorage) subtreeIDs(ctx context.Context, id types.ObjectId) ([]types.ObjectId, error) {
+	filter := bson.D{{"path._id", id}}
+	cursor, err := s.collection(ctx, DirectoryCollection).
+		Find(ctx, filter, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find subtree: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var directories []core.Directory
+	if err := cursor.All(ctx, &directories); err != nil {
+		return nil, fmt.Errorf("unable to decode subtree: %w", err)
+	}
+
+	ids := make([]types.ObjectId, 0, len(directories)+1)
+	ids = append(ids, id)
+	for _, directory := range directories {
+		ids = append(ids, directory.ID)
+	}
+
+	return ids, nil
+}
+
+// GetByPath walks the tree of the user from the root directory following names.
+func (s *DirectoryStorage) GetByPath(ctx context.Context, userID string, names []string) (*core.Directory, error) {
+	ctx, end := s.observe(ctx, "DirectoryStorage.GetByPath")
+	defer end()
+
+	var directory core.Directory
+	err := s.collection(ctx, DirectoryCollection).
+		FindOne(ctx, bson.D{{"userID", userID}, {"path", nil}}).
+		Decode(&directory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find root directory: %w", err)
+	}
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		var child core.Directory
+		filter := bson.D{{"userID", userID}, {"parentDirectoryID", string(directory.ID)}, {"name", name}}
+		err := s.collection(ctx, DirectoryCollection).
+			FindOne(ctx, filter).
+			Decode(&child)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find directory %v: %w", name, err)
+		}
+		directory = child
+	}
+
+	return &directory, nil
+}
+
+func (s *DirectoryStorage) GetTag(ctx context.Context, id types.ObjectId) (*core.Tag, error) {
+	return NewTagStorage(&s.Storage).GetTag(ctx, id)
+}
+
+func (s *DirectoryStorage) AddTag(ctx context.Context, id types.ObjectId, tag core.TagRef) error {
+	ctx, end := s.observe(ctx, "DirectoryStorage.AddTag")
+	defer end()
+
+	return s.updateTags(ctx, id, "$addToSet", tag)
+}
+
+func (s *DirectoryStorage) RemoveTag(ctx context.Context, id, tagID types.ObjectId) error {
+	ctx, end := s.observe(ctx, "DirectoryStorage.RemoveTag")
+	defer end()
+
+	return s.updateTags(ctx, id, "$pull", bson.D{{"_id", tagID}})
+}
+
+func (s *DirectoryStorage) updateTags(ctx context.Context, id types.ObjectId, operator string, value any) error {
+	timestamp := time.Now()
+
+	update := bson.D{{operator, bson.D{{"tags", value}}}, {"$set", bson.D{{"updatedAt", timestamp}}}}
+	revision, err := s.updateRevised(ctx, DirectoryCollection, id, update)
+	if err != nil {
+		return fmt.Errorf("unable to update directory tags: %w", err)
+	}
+
+	filter := bson.D{{"directories._id", id}}
+	update = bson.D{{operator, bson.D{{"directories.$.tags", value}}}, {"$set", bson.D{{"directories.$.updatedAt", timestamp}, {"directories.$.revision", revision}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update directory tags inside dir: %w", err)
+	}
+
+	return nil
+}
@@ -2,11 +2,15 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/search"
 	"github.com/mbretter/go-mongodb/types"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"time"
 )
 
@@ -20,13 +24,19 @@ func NewFileStorage(s *Storage) *FileStorage {
 	return &FileStorage{*s}
 }
 
-func (s *FileStorage) GetDirectory(ctx context.Context, id types.ObjectId) (*core.Directory, error) {
-	db := s.db
+func (s *Storage) GetDirectory(ctx context.Context, id types.ObjectId) (*core.Directory, error) {
+	ctx, end := s.observe(ctx, "Storage.GetDirectory")
+	defer end()
+
+	return s.getDirectory(ctx, id)
+}
 
+// getDirectory is GetDirectory for storage methods, which are timed themselves.
+func (s *Storage) getDirectory(ctx context.Context, id types.ObjectId) (*core.Directory, error) {
 	filter := bson.D{{"_id", id}}
 
 	var directory core.Directory
-	err := db.Collection(DirectoryCollection).
+	err := s.collection(ctx, DirectoryCollection).
 		FindOne(ctx, filter).
 		Decode(&directory)
 
@@ -34,12 +44,18 @@ func (s *FileStorage) GetDirectory(ctx context.Context, id types.ObjectId) (*cor
 }
 
 func (s *FileStorage) Get(ctx context.Context, id types.ObjectId) (*core.File, error) {
-	db := s.db
+	ctx, end := s.observe(ctx, "FileStorage.Get")
+	defer end()
+
+	return s.get(ctx, id)
+}
 
+// get is Get for storage methods, which are timed themselves.
+func (s *FileStorage) get(ctx context.Context, id types.ObjectId) (*core.File, error) {
 	filter := bson.D{{"_id", id}}
 
 	var file core.File
-	err := db.Collection(FileCollection).
+	err := s.collection(ctx, FileCollection).
 		FindOne(ctx, filter).
 		Decode(&file)
 
@@ -47,15 +63,19 @@ func (s *FileStorage) Get(ctx context.Context, id types.ObjectId) (*core.File, e
 }
 
 func (s *FileStorage) Create(ctx context.Context, parentDirID types.ObjectId, userID string, name, extension string, size uint) (*core.File, error) {
+	ctx, end := s.observe(ctx, "FileStorage.Create")
+	defer end()
+
 	db := s.db
 
-	dir, err := s.GetDirectory(ctx, parentDirID)
+	dir, err := s.getDirectory(ctx, parentDirID)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get parent directory: %w", err)
 	}
 
 	file := core.File{
 		UserID:            userID,
+		TenantID:          tenantOf(ctx),
 		ParentDirectoryID: string(parentDirID),
 		Starred:           false,
 		CreatedAt:         time.Now(),
@@ -64,14 +84,17 @@ func (s *FileStorage) Create(ctx context.Context, parentDirID types.ObjectId, us
 		Public:            false,
 		Name:              name,
 		Extension:         extension,
+		MimeType:          core.MimeTypeByExtension(extension),
 		Attrs:             map[string]string{},
+		Keywords:          s.index.Keywords(name, nil),
 	}
 
-	result, err := db.Collection(FileCollection).
+	result, err := s.collection(ctx, FileCollection).
 		InsertOne(ctx, file)
 	if err != nil {
 		return nil, fmt.Errorf("unable to insert file: %w", err)
 	}
+	s.m.Created("file")
 
 	id, ok := result.InsertedID.(primitive.ObjectID)
 	if !ok {
@@ -79,9 +102,11 @@ func (s *FileStorage) Create(ctx context.Context, parentDirID types.ObjectId, us
 	}
 	file.ID = types.ObjectId(id.Hex())
 
+	embedded := file
+	embedded.Keywords = nil
 	filter := bson.D{{"_id", parentDirID}}
-	update := bson.D{{"$push", bson.D{{"files", file}}}, {"$inc", bson.D{{"filesCount", 1}, {"size", size}}}}
-	_, err = db.Collection(DirectoryCollection).
+	update := bson.D{{"$push", bson.D{{"files", embedded}}}, {"$inc", bson.D{{"filesCount", 1}, {"size", size}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
@@ -102,23 +127,36 @@ func (s *FileStorage) Create(ctx context.Context, parentDirID types.ObjectId, us
 }
 
 func (s *FileStorage) Delete(ctx context.Context, id types.ObjectId) error {
+	ctx, end := s.observe(ctx, "FileStorage.Delete")
+	defer end()
+
 	db := s.db
 
-	file, err := s.Get(ctx, id)
+	file, err := s.get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("unable to find file: %w", err)
 	}
-	dir, err := s.GetDirectory(ctx, types.ObjectId(file.ParentDirectoryID))
+	dir, err := s.getDirectory(ctx, types.ObjectId(file.ParentDirectoryID))
 	if err != nil {
 		return fmt.Errorf("unable to get parent directory: %w", err)
 	}
 
-	filter := bson.D{{"_id", types.ObjectId(file.ParentDirectoryID)}}
+	// the file goes first, the write conditional on its revision must fail before anything else changed
+	filter := bson.D{{"_id", id}}
+	result, err := s.collection(ctx, FileCollection).DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("unable to delete file: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("unable to delete file: %w", mongo.ErrNoDocuments)
+	}
+
+	filter = bson.D{{"_id", types.ObjectId(file.ParentDirectoryID)}}
 	update := bson.D{
 		{"$pull", bson.D{{"files", bson.D{{"_id", id}}}}},
 		{"$inc", bson.D{{"filesCount", -1}, {"size", -int(file.Size)}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
@@ -131,42 +169,39 @@ func (s *FileStorage) Delete(ctx context.Context, id types.ObjectId) error {
 		return err
 	}
 
-	if err := IncrementSizes(db, ctx, dir.Path, -int(file.Size)); err != nil {
-		return err
-	}
-
-	return s.StupidDelete(ctx, id)
+	return IncrementSizes(db, ctx, dir.Path, -int(file.Size))
 }
 
 func (s *FileStorage) StupidDelete(ctx context.Context, id types.ObjectId) error {
-	db := s.db
+	ctx, end := s.observe(ctx, "FileStorage.StupidDelete")
+	defer end()
 
 	filter := bson.D{{"_id", id}}
-	_, err := db.Collection(FileCollection).DeleteOne(ctx, filter)
+	_, err := s.collection(ctx, FileCollection).DeleteOne(ctx, filter)
 
 	return err
 }
 
 func (s *FileStorage) Rename(ctx context.Context, id types.ObjectId, newName string) error {
-	db := s.db
+	ctx, end := s.observe(ctx, "FileStorage.Rename")
+	defer end()
 
 	timestamp := time.Now()
 
-	filter := bson.D{{"_id", id}}
-	update := bson.D{{"$set", bson.D{{"name", newName}, {"updatedAt", timestamp}}}}
-	_, err := db.Collection(FileCollection).
-		UpdateOne(
-			ctx,
-			filter,
-			update,
-		)
+	file, err := s.get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find file: %w", err)
+	}
+
+	update := bson.D{{"$set", bson.D{{"name", newName}, {"updatedAt", timestamp}, {search.KeywordsField, s.index.Keywords(newName, file.Attrs)}}}}
+	revision, err := s.updateRevised(ctx, FileCollection, id, update)
 	if err != nil {
 		return fmt.Errorf("unable to update name of the directory: %w", err)
 	}
 
-	filter = bson.D{{"files._id", id}}
-	update = bson.D{{"$set", bson.D{{"files.$.name", newName}, {"files.$.updatedAt", timestamp}}}}
-	_, err = db.Collection(DirectoryCollection).
+	filter := bson.D{{"files._id", id}}
+	update = bson.D{{"$set", bson.D{{"files.$.name", newName}, {"files.$.updatedAt", timestamp}, {"files.$.revision", revision}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -176,29 +211,77 @@ func (s *FileStorage) Rename(ctx context.Context, id types.ObjectId, newName str
 	return err
 }
 
+// Move moves the file into toID. If toID belongs to another user or workspace, the file is handed over to them,
+// when that fails the file is moved back, so it never stays in the tree of another owner.
 func (s *FileStorage) Move(ctx context.Context, id, toID types.ObjectId) error {
+	ctx, end := s.observe(ctx, "FileStorage.Move")
+	defer end()
+
+	file, err := s.get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find file: %w", err)
+	}
+	toDir, err := s.getDirectory(ctx, toID)
+	if err != nil {
+		return fmt.Errorf("unable to get target directory: %w", err)
+	}
+
+	if err := s.move(ctx, id, toID); err != nil {
+		return err
+	}
+	if file.UserID == toDir.UserID {
+		return nil
+	}
+
+	if err := s.setOwner(ctx, id, toDir.UserID); err != nil {
+		rollbackErr := errors.Join(
+			s.setOwner(ctx, id, file.UserID),
+			s.move(ctx, id, types.ObjectId(file.ParentDirectoryID)),
+		)
+		if rollbackErr != nil {
+			return fmt.Errorf("unable to set owner: %w, unable to move file back: %w", err, rollbackErr)
+		}
+
+		return fmt.Errorf("unable to set owner: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStorage) move(ctx context.Context, id, toID types.ObjectId) error {
 	db := s.db
 
-	file, err := s.Get(ctx, id)
+	file, err := s.get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("unable to find file: %w", err)
 	}
-	file.UpdatedAt = time.Now()
-	fromDir, err := s.GetDirectory(ctx, types.ObjectId(file.ParentDirectoryID))
+	fromDir, err := s.getDirectory(ctx, types.ObjectId(file.ParentDirectoryID))
 	if err != nil {
 		return fmt.Errorf("unable to get parent directory: %w", err)
 	}
-	toDir, err := s.GetDirectory(ctx, toID)
+	toDir, err := s.getDirectory(ctx, toID)
 	if err != nil {
 		return fmt.Errorf("unable to get target directory: %w", err)
 	}
 
-	filter := bson.D{{"_id", types.ObjectId(file.ParentDirectoryID)}}
-	update := bson.D{
+	// the file goes first, the write conditional on its revision must fail before anything else changed
+	filter := bson.D{{"_id", id}}
+	update := bson.D{{"$set", bson.D{{"parentDirectoryID", string(toID)}, {"updatedAt", time.Now()}}}}
+	var moved core.File
+	err = s.collection(ctx, FileCollection).
+		FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&moved)
+	if err != nil {
+		return fmt.Errorf("unable to update file parentID: %w", err)
+	}
+	moved.Keywords = nil
+
+	filter = bson.D{{"_id", types.ObjectId(file.ParentDirectoryID)}}
+	update = bson.D{
 		{"$pull", bson.D{{"files", bson.D{{"_id", id}}}}},
 		{"$inc", bson.D{{"filesCount", -1}, {"size", -int(file.Size)}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
@@ -211,25 +294,12 @@ func (s *FileStorage) Move(ctx context.Context, id, toID types.ObjectId) error {
 		return err
 	}
 
-	filter = bson.D{{"_id", id}}
-	update = bson.D{{"$set", bson.D{{"parentDirectoryID", string(toID)}, {"updatedAt", file.UpdatedAt}}}}
-	_, err = db.Collection(FileCollection).
-		UpdateMany(
-			ctx,
-			filter,
-			update,
-		)
-	if err != nil {
-		return fmt.Errorf("unable to update file parentID: %w", err)
-	}
-	file.ParentDirectoryID = string(toID)
-
 	filter = bson.D{{"_id", toID}}
 	update = bson.D{
-		{"$push", bson.D{{"files", file}}},
+		{"$push", bson.D{{"files", moved}}},
 		{"$inc", bson.D{{"filesCount", 1}, {"size", file.Size}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateOne(
 			ctx,
 			filter,
@@ -253,38 +323,44 @@ func (s *FileStorage) Move(ctx context.Context, id, toID types.ObjectId) error {
 	return nil
 }
 
+// setOwner hands the file over to another user or workspace, see core.WorkspaceOwner.
+func (s *FileStorage) setOwner(ctx context.Context, id types.ObjectId, owner string) error {
+	return s.updateField(ctx, id, "userID", owner)
+}
+
 func (s *FileStorage) Update(ctx context.Context, id types.ObjectId, size uint) error {
+	ctx, end := s.observe(ctx, "FileStorage.Update")
+	defer end()
+
 	db := s.db
 
-	file, err := s.Get(ctx, id)
+	file, err := s.get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("unable to find file: %w", err)
 	}
 	diff := int(size) - int(file.Size)
 	file.UpdatedAt = time.Now()
-	dir, err := s.GetDirectory(ctx, types.ObjectId(file.ParentDirectoryID))
+	dir, err := s.getDirectory(ctx, types.ObjectId(file.ParentDirectoryID))
 	if err != nil {
 		return fmt.Errorf("unable to get directory: %w", err)
 	}
 
-	filter := bson.D{{"_id", id}}
-	update := bson.D{{"$set", bson.D{{"size", size}, {"updatedAt", file.UpdatedAt}}}}
-	_, err = db.Collection(FileCollection).
-		UpdateMany(
-			ctx,
-			filter,
-			update,
-		)
+	update := bson.D{
+		{"$set", bson.D{{"size", size}, {"updatedAt", file.UpdatedAt}}},
+		{"$unset", bson.D{{"hash", ""}}}, // stale until the FS commits the new content
+	}
+	revision, err := s.updateRevised(ctx, FileCollection, id, update)
 	if err != nil {
 		return fmt.Errorf("unable to update file size: %w", err)
 	}
 
-	filter = bson.D{{"files._id", id}}
+	filter := bson.D{{"files._id", id}}
 	update = bson.D{
-		{"$set", bson.D{{"files.$.size", size}, {"files.$.updatedAt", file.UpdatedAt}}},
+		{"$set", bson.D{{"files.$.size", size}, {"files.$.updatedAt", file.UpdatedAt}, {"files.$.revision", revision}}},
+		{"$unset", bson.D{{"files.$.hash", ""}}},
 		{"$inc", bson.D{{"size", diff}}},
 	}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
@@ -304,46 +380,194 @@ func (s *FileStorage) Update(ctx context.Context, id types.ObjectId, size uint)
 	return err
 }
 
-func (s *FileStorage) Star(ctx context.Context, id types.ObjectId) error {
-	file, err := s.Get(ctx, id)
-	if err != nil {
-		return fmt.Errorf("unable to find file: %w", err)
-	}
+func (s *FileStorage) Star(ctx context.Context, id types.ObjectId, starred bool) error {
+	ctx, end := s.observe(ctx, "FileStorage.Star")
+	defer end()
 
-	return s.UpdateField(ctx, id, "starred", !file.Starred)
+	return s.updateField(ctx, id, "starred", starred)
 }
 
 func (s *FileStorage) Share(ctx context.Context, id types.ObjectId, mode bool) error {
-	return s.UpdateField(ctx, id, "public", mode)
+	ctx, end := s.observe(ctx, "FileStorage.Share")
+	defer end()
+
+	return s.updateField(ctx, id, "public", mode)
 }
 
-func (s *FileStorage) UpdateField(ctx context.Context, id types.ObjectId, field string, value any) error {
-	db := s.db
+func (s *FileStorage) updateField(ctx context.Context, id types.ObjectId, field string, value any) error {
 	timestamp := time.Now()
 
-	filter := bson.D{{"_id", id}}
 	update := bson.D{{"$set", bson.D{{field, value}, {"updatedAt", timestamp}}}}
-	_, err := db.Collection(FileCollection).
+	revision, err := s.updateRevised(ctx, FileCollection, id, update)
+	if err != nil {
+		return fmt.Errorf("unable to update file field: %w", err)
+	}
+
+	filter := bson.D{{"files._id", id}}
+	update = bson.D{{"$set", bson.D{{"files.$." + field, value}, {"files.$.updatedAt", timestamp}, {"files.$.revision", revision}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
 			update,
 		)
 	if err != nil {
-		return fmt.Errorf("unable to update file field: %w", err)
+		return fmt.Errorf("unable to update file field inside dir: %w", err)
+	}
+
+	return err
+}
+
+func (s *FileStorage) GetTag(ctx context.Context, id types.ObjectId) (*core.Tag, error) {
+	return NewTagStorage(&s.Storage).GetTag(ctx, id)
+}
+
+func (s *FileStorage) AddTag(ctx context.Context, id types.ObjectId, tag core.TagRef) error {
+	ctx, end := s.observe(ctx, "FileStorage.AddTag")
+	defer end()
+
+	return s.updateTags(ctx, id, "$addToSet", tag)
+}
+
+func (s *FileStorage) RemoveTag(ctx context.Context, id, tagID types.ObjectId) error {
+	ctx, end := s.observe(ctx, "FileStorage.RemoveTag")
+	defer end()
+
+	return s.updateTags(ctx, id, "$pull", bson.D{{"_id", tagID}})
+}
+
+func (s *FileStorage) updateTags(ctx context.Context, id types.ObjectId, operator string, value any) error {
+	timestamp := time.Now()
+
+	update := bson.D{{operator, bson.D{{"tags", value}}}, {"$set", bson.D{{"updatedAt", timestamp}}}}
+	revision, err := s.updateRevised(ctx, FileCollection, id, update)
+	if err != nil {
+		return fmt.Errorf("unable to update file tags: %w", err)
 	}
 
-	filter = bson.D{{"files._id", id}}
-	update = bson.D{{"$set", bson.D{{"files.$." + field, value}, {"files.$.updatedAt", timestamp}}}}
-	_, err = db.Collection(DirectoryCollection).
+	filter := bson.D{{"files._id", id}}
+	update = bson.D{{operator, bson.D{{"files.$.tags", value}}}, {"$set", bson.D{{"files.$.updatedAt", timestamp}, {"files.$.revision", revision}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
 		UpdateMany(
 			ctx,
 			filter,
 			update,
 		)
 	if err != nil {
-		return fmt.Errorf("unable to update file field inside dir: %w", err)
+		return fmt.Errorf("unable to update file tags inside dir: %w", err)
 	}
 
-	return err
+	return nil
+}
+
+// UpdateAttrs replaces the attributes of the file and the keywords derived from them.
+func (s *FileStorage) UpdateAttrs(ctx context.Context, id types.ObjectId, attrs map[string]string) error {
+	ctx, end := s.observe(ctx, "FileStorage.UpdateAttrs")
+	defer end()
+
+	timestamp := time.Now()
+
+	file, err := s.get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find file: %w", err)
+	}
+
+	update := bson.D{{"$set", bson.D{{"attrs", attrs}, {"updatedAt", timestamp}, {search.KeywordsField, s.index.Keywords(file.Name, attrs)}}}}
+	revision, err := s.updateRevised(ctx, FileCollection, id, update)
+	if err != nil {
+		return fmt.Errorf("unable to update file attrs: %w", err)
+	}
+
+	filter := bson.D{{"files._id", id}}
+	update = bson.D{{"$set", bson.D{{"files.$.attrs", attrs}, {"files.$.updatedAt", timestamp}, {"files.$.revision", revision}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update file attrs inside dir: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStorage) SetHash(ctx context.Context, id types.ObjectId, hash string) error {
+	ctx, end := s.observe(ctx, "FileStorage.SetHash")
+	defer end()
+
+	return s.updateField(ctx, id, "hash", hash)
+}
+
+func (s *FileStorage) SetMimeType(ctx context.Context, id types.ObjectId, mimeType string) error {
+	ctx, end := s.observe(ctx, "FileStorage.SetMimeType")
+	defer end()
+
+	return s.updateField(ctx, id, "mimeType", mimeType)
+}
+
+// GetUsage returns the number and total size of the files of the user per MIME type.
+func (s *FileStorage) GetUsage(ctx context.Context, userID string) ([]core.MimeTypeUsage, error) {
+	ctx, end := s.observe(ctx, "FileStorage.GetUsage")
+	defer end()
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"userID", userID}}}},
+		{{"$group", bson.D{
+			{"_id", "$mimeType"},
+			{"count", bson.D{{"$sum", 1}}},
+			{"size", bson.D{{"$sum", "$size"}}},
+		}}},
+	}
+
+	cursor, err := s.collection(ctx, FileCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to aggregate usage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	usage := []core.MimeTypeUsage{}
+	if err := cursor.All(ctx, &usage); err != nil {
+		return nil, fmt.Errorf("unable to decode usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// GetDuplicates returns the groups of files of the user sharing a hash,
+// ordered by the space they waste.
+func (s *FileStorage) GetDuplicates(ctx context.Context, userID string, offset, limit uint) ([]core.Duplicates, error) {
+	ctx, end := s.observe(ctx, "FileStorage.GetDuplicates")
+	defer end()
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"userID", userID}, {"hash", bson.D{{"$exists", true}, {"$ne", ""}}}}}},
+		{{"$project", bson.D{{search.KeywordsField, 0}}}},
+		{{"$sort", bson.D{{"createdAt", 1}, {"_id", 1}}}},
+		{{"$group", bson.D{
+			{"_id", "$hash"},
+			{"size", bson.D{{"$first", "$size"}}},
+			{"count", bson.D{{"$sum", 1}}},
+			{"files", bson.D{{"$push", "$$ROOT"}}},
+		}}},
+		{{"$match", bson.D{{"count", bson.D{{"$gt", 1}}}}}},
+		{{"$addFields", bson.D{{"wasted", bson.D{{"$multiply", bson.A{"$size", bson.D{{"$subtract", bson.A{"$count", 1}}}}}}}}}},
+		{{"$sort", bson.D{{"wasted", -1}, {"_id", 1}}}},
+		{{"$skip", offset}},
+		{{"$limit", limit}},
+	}
+
+	cursor, err := s.collection(ctx, FileCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to aggregate duplicates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	duplicates := []core.Duplicates{}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return nil, fmt.Errorf("unable to decode duplicates: %w", err)
+	}
+
+	return duplicates, nil
 }
@@ -4,81 +4,154 @@ import (
 	"context"
 	"fmt"
 	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/search"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+type searchResult struct {
+	Counts []struct {
+		Type  string `bson:"_id"`
+		Count uint   `bson:"count"`
+	} `bson:"counts"`
+	Items []bson.Raw `bson:"items"`
+}
+
 func (s *DirectoryStorage) GetGlobalWithPaginationAndFiltering(
 	ctx context.Context,
 	userID string,
+	query string,
 	directoryFilter bson.D,
 	fileFilter bson.D,
 	offset, limit uint,
-	sortByField string,
-	sortOrder int,
+	sort core.Sort,
 ) (*core.DirectoryLike, error) {
-	var result core.DirectoryLike
-
-	if directoryFilter != nil {
-		cursor, err := s.db.Collection(DirectoryCollection).Aggregate(
-			ctx,
-			aggregationFilter(userID, directoryFilter, DirectoryCollection, offset, limit, sortByField, sortOrder),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute aggregation: %w", err)
-		}
-		defer cursor.Close(ctx)
+	ctx, end := s.observe(ctx, "DirectoryStorage.GetGlobalWithPaginationAndFiltering")
+	defer end()
 
-		var directories []core.DirectoryLike
-		if err = cursor.All(ctx, &directories); err != nil {
-			return nil, fmt.Errorf("failed to decode directory: %w", err)
-		}
+	result := core.DirectoryLike{
+		Directories: []core.Directory{},
+		Files:       []core.File{},
+		Items:       []core.ItemRef{},
+	}
 
-		if len(directories) == 0 {
-			return nil, fmt.Errorf("directory not found")
+	match, score := s.index.Match(query)
+	if match != nil {
+		if directoryFilter != nil {
+			directoryFilter = append(directoryFilter, match...)
+		}
+		if fileFilter != nil {
+			fileFilter = append(fileFilter, match...)
 		}
-		directoriesResult := directories[0]
-		result.DirectoriesCount = directoriesResult.DirectoriesCount
-		result.Directories = directoriesResult.Directories
 	}
 
-	if fileFilter != nil {
-		cursor, err := s.db.Collection(FileCollection).Aggregate(
-			ctx,
-			aggregationFilter(userID, fileFilter, FileCollection, offset, limit, sortByField, sortOrder),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute aggregation: %w", err)
-		}
-		defer cursor.Close(ctx)
+	// files may be joined with $unionWith, which is out of reach of the scoped collection
+	fileFilter = tenantFilter(ctx, fileFilter)
+
+	collection, pipeline := aggregationFilter(userID, directoryFilter, fileFilter, score, offset, limit, sort)
+	if pipeline == nil {
+		return &result, nil
+	}
 
-		var directories []core.DirectoryLike
-		if err = cursor.All(ctx, &directories); err != nil {
-			return nil, fmt.Errorf("failed to decode directory: %w", err)
+	cursor, err := s.collection(ctx, collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []searchResult
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode search result: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("search result not found")
+	}
+
+	for _, count := range results[0].Counts {
+		switch count.Type {
+		case core.DirectoryItem:
+			result.DirectoriesCount = count.Count
+		case core.FileItem:
+			result.FilesCount = count.Count
 		}
+	}
+
+	for _, raw := range results[0].Items {
+		itemType, _ := raw.Lookup("_type").StringValueOK()
 
-		if len(directories) == 0 {
-			return nil, fmt.Errorf("directory not found")
+		switch itemType {
+		case core.DirectoryItem:
+			var directory core.Directory
+			if err := bson.Unmarshal(raw, &directory); err != nil {
+				return nil, fmt.Errorf("failed to decode directory: %w", err)
+			}
+			result.Directories = append(result.Directories, directory)
+			result.Items = append(result.Items, core.ItemRef{ID: directory.ID, Type: itemType})
+		case core.FileItem:
+			var file core.File
+			if err := bson.Unmarshal(raw, &file); err != nil {
+				return nil, fmt.Errorf("failed to decode file: %w", err)
+			}
+			result.Files = append(result.Files, file)
+			result.Items = append(result.Items, core.ItemRef{ID: file.ID, Type: itemType})
 		}
-		directoriesResult := directories[0]
-		result.FilesCount = directoriesResult.FilesCount
-		result.Files = directoriesResult.Files
 	}
 
 	return &result, nil
 }
 
-func aggregationFilter(userID string, filter bson.D, name string, offset, limit uint, sortByField string, sortOrder int) []bson.D {
-	if name == DirectoryCollection {
-		filter = append(filter, bson.E{"name", bson.M{"$ne": "root"}})
-	}
+// aggregationFilter builds a single pipeline over directories and files, so both are sorted
+// together before the page is cut. Non-nil score is stored as _score to sort by relevance.
+// It returns the collection the pipeline must run on, the pipeline is nil when both filters are nil.
+func aggregationFilter(
+	userID string,
+	directoryFilter, fileFilter bson.D,
+	score any,
+	offset, limit uint,
+	sort core.Sort,
+) (string, []bson.D) {
+	var (
+		collection string
+		pipeline   []bson.D
+	)
 
-	filter = append(filter, bson.E{"userID", userID})
-	result := []bson.D{
-		{{"$match", filter}},
+	switch {
+	case directoryFilter != nil:
+		collection = DirectoryCollection
+		pipeline = directoriesStages(userID, directoryFilter, score)
+		if fileFilter != nil {
+			pipeline = append(pipeline, bson.D{{"$unionWith", bson.D{
+				{"coll", FileCollection},
+				{"pipeline", filesStages(userID, fileFilter, score)},
+			}}})
+		}
+	case fileFilter != nil:
+		collection = FileCollection
+		pipeline = filesStages(userID, fileFilter, score)
+	default:
+		return "", nil
 	}
 
-	if name == DirectoryCollection {
-		result = append(result, bson.D{{"$project", bson.M{
+	pipeline = append(pipeline, bson.D{{"$facet", bson.D{
+		{"counts", []bson.D{
+			{{"$group", bson.D{{"_id", "$_type"}, {"count", bson.D{{"$sum", 1}}}}}},
+		}},
+		{"items", []bson.D{
+			{{"$sort", sort.ToMongo()}},
+			{{"$skip", int64(offset)}},
+			{{"$limit", int64(limit)}},
+		}},
+	}}})
+
+	return collection, pipeline
+}
+
+func directoriesStages(userID string, filter bson.D, score any) []bson.D {
+	filter = append(filter, bson.E{"name", bson.M{"$ne": "root"}}, bson.E{"userID", userID})
+
+	return []bson.D{
+		{{"$match", filter}},
+		{{"$addFields", bson.D{{"_score", score}}}},
+		{{"$project", bson.M{
 			"_id":               1,
 			"userID":            1,
 			"parentDirectoryID": 1,
@@ -89,23 +162,20 @@ func aggregationFilter(userID string, filter bson.D, name string, offset, limit
 			"public":            1,
 			"size":              1,
 			"starred":           1,
-		}}})
+			"tags":              1,
+			"revision":          1,
+			"_score":            1,
+		}}},
+		{{"$addFields", bson.D{{"_type", core.DirectoryItem}}}},
 	}
+}
 
-	result = append(result, []bson.D{
-		{{"$facet", bson.D{
-			{"result", []bson.D{{{"$count", name + "Count"}}}},
-			{name, []bson.D{
-				{{"$skip", offset}},
-				{{"$limit", limit}},
-				{{"$sort", bson.D{{sortByField, sortOrder}}}},
-			}},
-		}}},
-		{{"$project", bson.M{
-			name + "Count": bson.M{"$arrayElemAt": []interface{}{"$result." + name + "Count", 0}},
-			name:           1,
-		}}},
-	}...)
+func filesStages(userID string, filter bson.D, score any) []bson.D {
+	filter = append(filter, bson.E{"userID", userID})
 
-	return result
+	return []bson.D{
+		{{"$match", filter}},
+		{{"$addFields", bson.D{{"_type", core.FileItem}, {"_score", score}}}},
+		{{"$project", bson.D{{search.KeywordsField, 0}}}},
+	}
 }
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAggregationFilterSortsBeforePaging(t *testing.T) {
+	sort := core.Sort{{"_type", 1}, {"name", 1}}
+	collection, pipeline := aggregationFilter("user", bson.D{}, bson.D{}, nil, 20, 10, sort)
+
+	require.Equal(t, DirectoryCollection, collection)
+
+	// the files are merged into the directories before anything is sorted
+	var stages []string
+	for _, stage := range pipeline {
+		stages = append(stages, stage[0].Key)
+	}
+	require.Equal(t, []string{"$match", "$addFields", "$project", "$addFields", "$unionWith", "$facet"}, stages)
+
+	facet := pipeline[len(pipeline)-1][0].Value.(bson.D)
+	require.Equal(t, "items", facet[1].Key)
+	require.Equal(t, []bson.D{
+		{{"$sort", bson.D{{"_type", 1}, {"name", 1}, {"_id", 1}}}},
+		{{"$skip", int64(20)}},
+		{{"$limit", int64(10)}},
+	}, facet[1].Value)
+}
+
+func TestAggregationFilterSingleCollection(t *testing.T) {
+	collection, pipeline := aggregationFilter("user", nil, bson.D{}, nil, 0, 10, nil)
+	require.Equal(t, FileCollection, collection)
+	require.NotEmpty(t, pipeline)
+
+	collection, pipeline = aggregationFilter("user", nil, nil, nil, 0, 10, nil)
+	require.Empty(t, collection)
+	require.Nil(t, pipeline)
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/search"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (s *Storage) ensureIndexes(ctx context.Context) error {
+	indexes := map[string][]mongo.IndexModel{
+		DirectoryCollection: {
+			{Keys: bson.D{{"userID", 1}, {search.KeywordsField, 1}}},
+		},
+		FileCollection: {
+			{Keys: bson.D{{"userID", 1}, {search.KeywordsField, 1}}},
+			{Keys: bson.D{{"userID", 1}, {"hash", 1}}, Options: options.Index().SetSparse(true)},
+		},
+		TagCollection: {
+			{Keys: bson.D{{"userID", 1}, {"name", 1}}, Options: options.Index().SetUnique(true)},
+		},
+		AttrSchemaCollection: {
+			{Keys: bson.D{{"userID", 1}, {"directoryID", 1}}, Options: options.Index().SetUnique(true)},
+		},
+		ActivityCollection: {
+			{Keys: bson.D{{"userID", 1}, {"fileID", 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{"userID", 1}, {lastActivity, -1}}},
+			{Keys: bson.D{{"userID", 1}, {openedActivity, -1}}, Options: options.Index().SetSparse(true)},
+			{Keys: bson.D{{"userID", 1}, {modifiedActivity, -1}}, Options: options.Index().SetSparse(true)},
+			{Keys: bson.D{{"expiresAt", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		ShortcutCollection: {
+			{Keys: bson.D{{"parentDirectoryID", 1}}},
+		},
+		WorkspaceCollection: {
+			{Keys: bson.D{{"members.userID", 1}}},
+		},
+		AuditCollection: {
+			{Keys: bson.D{{"tenantID", 1}, {"createdAt", -1}}},
+			{Keys: bson.D{{"tenantID", 1}, {"actor", 1}, {"createdAt", -1}}},
+			{Keys: bson.D{{"tenantID", 1}, {"targets", 1}, {"createdAt", -1}}},
+		},
+		APITokenCollection: {
+			{Keys: bson.D{{"hash", 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{"userID", 1}, {"createdAt", -1}}},
+			{Keys: bson.D{{"expiresAt", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		RateLimitCollection: {
+			{Keys: bson.D{{"expiresAt", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		SavedSearchCollection: {
+			{Keys: bson.D{{"userID", 1}, {"name", 1}}},
+		},
+	}
+
+	for collection, models := range indexes {
+		if _, err := s.db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("unable to create indexes for %v: %w", collection, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) backfillKeywords(ctx context.Context) error {
+	for _, collection := range []string{DirectoryCollection, FileCollection} {
+		filter := bson.D{{search.KeywordsField, bson.D{{"$exists", false}}}}
+		cursor, err := s.db.Collection(collection).Find(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("unable to find documents without keywords: %w", err)
+		}
+
+		for cursor.Next(ctx) {
+			var entry struct {
+				ID    any               `bson:"_id"`
+				Name  string            `bson:"name"`
+				Attrs map[string]string `bson:"attrs"`
+			}
+			if err := cursor.Decode(&entry); err != nil {
+				cursor.Close(ctx)
+				return fmt.Errorf("unable to decode document: %w", err)
+			}
+
+			update := bson.D{{"$set", bson.D{{search.KeywordsField, s.index.Keywords(entry.Name, entry.Attrs)}}}}
+			if _, err := s.db.Collection(collection).UpdateByID(ctx, entry.ID, update); err != nil {
+				cursor.Close(ctx)
+				return fmt.Errorf("unable to set keywords: %w", err)
+			}
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to iterate documents without keywords: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) backfillMimeTypes(ctx context.Context) error {
+	filter := bson.D{{"mimeType", bson.D{{"$exists", false}}}}
+	cursor, err := s.db.Collection(FileCollection).Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("unable to find files without mime type: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var file core.File
+		if err := cursor.Decode(&file); err != nil {
+			return fmt.Errorf("unable to decode file: %w", err)
+		}
+
+		mimeType := core.MimeTypeByExtension(file.Extension)
+		update := bson.D{{"$set", bson.D{{"mimeType", mimeType}}}}
+		if _, err := s.db.Collection(FileCollection).UpdateByID(ctx, file.ID, update); err != nil {
+			return fmt.Errorf("unable to set mime type: %w", err)
+		}
+
+		update = bson.D{{"$set", bson.D{{"files.$.mimeType", mimeType}}}}
+		if _, err := s.db.Collection(DirectoryCollection).UpdateMany(ctx, bson.D{{"files._id", file.ID}}, update); err != nil {
+			return fmt.Errorf("unable to set mime type inside dir: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("unable to iterate files without mime type: %w", err)
+	}
+
+	return nil
+}
+
+// tenantCollections are the collections accessed through collection, their documents carry the tenant.
+var tenantCollections = []string{
+	DirectoryCollection,
+	FileCollection,
+	WorkspaceCollection,
+	ShortcutCollection,
+	TagCollection,
+	AttrSchemaCollection,
+	SavedSearchCollection,
+	ActivityCollection,
+}
+
+// backfillTenants assigns documents written before tenants existed to the default tenant "".
+func (s *Storage) backfillTenants(ctx context.Context) error {
+	filter := bson.D{{tenantField, bson.D{{"$exists", false}}}}
+	update := bson.D{{"$set", bson.D{{tenantField, ""}}}}
+	for _, collection := range tenantCollections {
+		if _, err := s.db.Collection(collection).UpdateMany(ctx, filter, update); err != nil {
+			return fmt.Errorf("unable to backfill tenant of %v: %w", collection, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// Lock acquires the lock unless another user holds a conflicting active lock, which returns mongo.ErrNoDocuments.
+// Expired locks and the previous lock of the same user are dropped.
+func (s *FileStorage) Lock(ctx context.Context, id types.ObjectId, lock core.Lock) (*core.File, error) {
+	ctx, end := s.observe(ctx, "FileStorage.Lock")
+	defer end()
+
+	now := time.Now()
+
+	conflict := bson.D{{"userID", bson.D{{"$ne", lock.UserID}}}, {"expiresAt", bson.D{{"$gt", now}}}}
+	if lock.Mode == core.LockShared {
+		conflict = append(conflict, bson.E{"mode", core.LockExclusive})
+	}
+	filter := bson.D{{"_id", id}, {"locks", bson.D{{"$not", bson.D{{"$elemMatch", conflict}}}}}}
+
+	kept := bson.D{{"$filter", bson.D{
+		{"input", bson.D{{"$ifNull", bson.A{"$locks", bson.A{}}}}},
+		{"cond", bson.D{{"$and", bson.A{
+			bson.D{{"$ne", bson.A{"$$this.userID", lock.UserID}}},
+			bson.D{{"$gt", bson.A{"$$this.expiresAt", now}}},
+		}}}},
+	}}}
+	update := []bson.D{{{"$set", bson.D{{"locks", bson.D{{"$concatArrays", bson.A{kept, bson.A{lock}}}}}}}}}
+
+	var file core.File
+	err := s.collection(ctx, FileCollection).
+		FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setEmbeddedLocks(ctx, id, file.Locks); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Unlock releases the lock of the user, all locks if userID is empty.
+func (s *FileStorage) Unlock(ctx context.Context, id types.ObjectId, userID string) error {
+	ctx, end := s.observe(ctx, "FileStorage.Unlock")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+	update := bson.D{{"$unset", bson.D{{"locks", ""}}}}
+	if userID != "" {
+		update = bson.D{{"$pull", bson.D{{"locks", bson.D{{"userID", userID}}}}}}
+	}
+
+	var file core.File
+	err := s.collection(ctx, FileCollection).
+		FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).
+		Decode(&file)
+	if err != nil {
+		return fmt.Errorf("unable to unlock file: %w", err)
+	}
+
+	return s.setEmbeddedLocks(ctx, id, file.Locks)
+}
+
+// LockedFor reports whether a user other than userID holds an active lock on a file in the directory or below it,
+// see core.LockedFor.
+func (s *DirectoryStorage) LockedFor(ctx context.Context, id types.ObjectId, userID string) (bool, error) {
+	ctx, end := s.observe(ctx, "DirectoryStorage.LockedFor")
+	defer end()
+
+	ids, err := s.subtreeIDs(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	parentIDs := make([]string, len(ids))
+	for i, id := range ids {
+		parentIDs[i] = string(id)
+	}
+
+	conflict := bson.D{{"userID", bson.D{{"$ne", userID}}}, {"expiresAt", bson.D{{"$gt", time.Now()}}}}
+	filter := bson.D{{"parentDirectoryID", bson.D{{"$in", parentIDs}}}, {"locks", bson.D{{"$elemMatch", conflict}}}}
+	err = s.collection(ctx, FileCollection).
+		FindOne(ctx, filter, options.FindOne().SetProjection(bson.D{{"_id", 1}})).
+		Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to find locked files: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *FileStorage) setEmbeddedLocks(ctx context.Context, id types.ObjectId, locks []core.Lock) error {
+	filter := bson.D{{"files._id", id}}
+	update := bson.D{{"$set", bson.D{{"files.$.locks", locks}}}}
+	if len(locks) == 0 {
+		update = bson.D{{"$unset", bson.D{{"files.$.locks", ""}}}}
+	}
+	_, err := s.collection(ctx, DirectoryCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update locks inside dir: %w", err)
+	}
+
+	return nil
+}
@@ -2,23 +2,101 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/metrics"
+	"github.com/StratuStore/fsm/internal/fsm/search"
+	"github.com/StratuStore/fsm/internal/fsm/tracing"
 	"github.com/StratuStore/fsm/internal/libs/config"
 	"github.com/cenkalti/backoff/v5"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
 )
 
 type Storage struct {
-	db *mongo.Database
+	db    *mongo.Database
+	index search.Index
+	m     *metrics.Metrics
+	t     trace.Tracer
+	l     *slog.Logger
+	// cancel stops prepare, done is closed once it returned
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
-func New(cfg *config.Config) *Storage {
+func New(l *slog.Logger, cfg *config.Config, index search.Index, m *metrics.Metrics, tp trace.TracerProvider) *Storage {
 	client, err := openConnection(cfg.MongoDB.MongoConnectionString(), cfg.MongoDB.MongoMaxRetries)
 	if err != nil {
 		panic(err)
 	}
 
-	return &Storage{client.Database(cfg.MongoDB.MongoDB)}
+	s := &Storage{
+		db:    client.Database(cfg.MongoDB.MongoDB),
+		index: index,
+		m:     m,
+		t:     tracing.Tracer(tp),
+		l:     l.With(slog.String("module", "internal.fsm.storage")),
+	}
+
+	return s
+}
+
+// Start assigns documents written before tenants existed to the default tenant, as the scoped collections would not
+// find them otherwise, and fails if it can not. The indexes and the other migrations are done in the background.
+func (s *Storage) Start(ctx context.Context) error {
+	if err := s.backfillTenants(ctx); err != nil {
+		return fmt.Errorf("unable to backfill tenants: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		s.prepare(ctx)
+	}()
+
+	return nil
+}
+
+// Stop cancels the migrations and waits for them to return.
+func (s *Storage) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observe times the storage method and traces it, the returned context carries the span.
+// Only the methods called from outside the storage observe, they call each other through unexported helpers,
+// so that every operation is timed once.
+//
+//	ctx, end := s.observe(ctx, "FileStorage.Get")
+//	defer end()
+func (s *Storage) observe(ctx context.Context, method string) (context.Context, func()) {
+	stop := s.m.ObserveStorage(method)
+	ctx, span := s.t.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient))
+
+	return ctx, func() {
+		span.End()
+		stop()
+	}
+}
+
+// Ping checks the connection to the primary.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.Client().Ping(ctx, readpref.Primary())
 }
 
 func openConnection(connectionString string, maxRetries uint) (*mongo.Client, error) {
@@ -33,3 +111,18 @@ func openConnection(connectionString string, maxRetries uint) (*mongo.Client, er
 		backoff.WithMaxTries(maxRetries),
 	)
 }
+
+// prepare creates the indexes and migrates documents written by older versions.
+func (s *Storage) prepare(ctx context.Context) {
+	l := s.l
+
+	if err := s.ensureIndexes(ctx); err != nil {
+		l.Error("unable to create indexes", slog.String("err", err.Error()))
+	}
+	if err := s.backfillKeywords(ctx); err != nil {
+		l.Error("unable to backfill search keywords", slog.String("err", err.Error()))
+	}
+	if err := s.backfillMimeTypes(ctx); err != nil {
+		l.Error("unable to backfill mime types", slog.String("err", err.Error()))
+	}
+}
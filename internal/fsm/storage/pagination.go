@@ -7,13 +7,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func (s *DirectoryStorage) WithPagination(
+func (s *DirectoryStorage) withPagination(
 	ctx context.Context,
 	filter bson.D,
 	offset, limit uint,
-	sortByField string,
-	sortOrder int,
-) (*core.Directory, error) {
+	sort core.Sort,
+) (*core.DirectoryPage, error) {
 	pipeline := []bson.M{
 		{"$match": filter},
 		{
@@ -32,6 +31,9 @@ func (s *DirectoryStorage) WithPagination(
 						"starred":           1,
 						"directoriesCount":  1,
 						"filesCount":        1,
+						"shortcutsCount":    1,
+						"tags":              1,
+						"revision":          1,
 					}},
 				},
 				"items": []bson.M{
@@ -40,31 +42,31 @@ func (s *DirectoryStorage) WithPagination(
 							"$concatArrays": []interface{}{
 								bson.M{
 									"$map": bson.M{
-										"input": bson.M{
-											"$sortArray": bson.M{
-												"input":  "$directories",
-												"sortBy": bson.M{sortByField: sortOrder},
-											},
-										},
-										"in": bson.M{"$mergeObjects": []interface{}{"$$this", bson.M{"_type": "dir"}}},
+										"input": "$directories",
+										"in":    bson.M{"$mergeObjects": []interface{}{"$$this", bson.M{"_type": core.DirectoryItem}}},
 									},
 								},
 								bson.M{
 									"$map": bson.M{
-										"input": bson.M{
-											"$sortArray": bson.M{
-												"input":  "$files",
-												"sortBy": bson.M{sortByField: sortOrder},
-											},
-										},
-										"in": bson.M{"$mergeObjects": []interface{}{"$$this", bson.M{"_type": "file"}}},
+										"input": "$files",
+										"in":    bson.M{"$mergeObjects": []interface{}{"$$this", bson.M{"_type": core.FileItem}}},
+									},
+								},
+								bson.M{
+									"$map": bson.M{
+										"input": bson.M{"$ifNull": []interface{}{"$shortcuts", []interface{}{}}},
+										"in":    bson.M{"$mergeObjects": []interface{}{"$$this", bson.M{"_type": core.ShortcutItem}}},
 									},
 								},
 							},
 						},
 					}},
 					{"$project": bson.M{
-						"items": bson.M{"$slice": []interface{}{"$allItems", int(offset), int(limit)}},
+						"items": bson.M{"$slice": []interface{}{
+							bson.M{"$sortArray": bson.M{"input": "$allItems", "sortBy": sort.ToMongo()}},
+							int(offset),
+							int(limit),
+						}},
 					}},
 				},
 			},
@@ -83,12 +85,20 @@ func (s *DirectoryStorage) WithPagination(
 				"starred":           bson.M{"$arrayElemAt": []interface{}{"$metadata.starred", 0}},
 				"directoriesCount":  bson.M{"$arrayElemAt": []interface{}{"$metadata.directoriesCount", 0}},
 				"filesCount":        bson.M{"$arrayElemAt": []interface{}{"$metadata.filesCount", 0}},
+				"shortcutsCount":    bson.M{"$arrayElemAt": []interface{}{"$metadata.shortcutsCount", 0}},
+				"tags":              bson.M{"$arrayElemAt": []interface{}{"$metadata.tags", 0}},
+				"items": bson.M{
+					"$map": bson.M{
+						"input": bson.M{"$arrayElemAt": []interface{}{"$items.items", 0}},
+						"in":    bson.M{"_id": "$$this._id", "_type": "$$this._type"},
+					},
+				},
 				"directories": bson.M{
 					"$map": bson.M{
 						"input": bson.M{
 							"$filter": bson.M{
 								"input": bson.M{"$arrayElemAt": []interface{}{"$items.items", 0}},
-								"cond":  bson.M{"$eq": []string{"$$this._type", "dir"}},
+								"cond":  bson.M{"$eq": []string{"$$this._type", core.DirectoryItem}},
 							},
 						},
 						"in": bson.M{
@@ -106,7 +116,25 @@ func (s *DirectoryStorage) WithPagination(
 						"input": bson.M{
 							"$filter": bson.M{
 								"input": bson.M{"$arrayElemAt": []interface{}{"$items.items", 0}},
-								"cond":  bson.M{"$eq": []string{"$$this._type", "file"}},
+								"cond":  bson.M{"$eq": []string{"$$this._type", core.FileItem}},
+							},
+						},
+						"in": bson.M{
+							"$arrayToObject": bson.M{
+								"$filter": bson.M{
+									"input": bson.M{"$objectToArray": "$$this"},
+									"cond":  bson.M{"$ne": []string{"$$this.k", "_type"}},
+								},
+							},
+						},
+					},
+				},
+				"shortcuts": bson.M{
+					"$map": bson.M{
+						"input": bson.M{
+							"$filter": bson.M{
+								"input": bson.M{"$arrayElemAt": []interface{}{"$items.items", 0}},
+								"cond":  bson.M{"$eq": []string{"$$this._type", core.ShortcutItem}},
 							},
 						},
 						"in": bson.M{
@@ -123,13 +151,13 @@ func (s *DirectoryStorage) WithPagination(
 		},
 	}
 
-	cursor, err := s.db.Collection(DirectoryCollection).Aggregate(ctx, pipeline)
+	cursor, err := s.collection(ctx, DirectoryCollection).Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	var directories []core.Directory
+	var directories []core.DirectoryPage
 	if err = cursor.All(ctx, &directories); err != nil {
 		return nil, fmt.Errorf("failed to decode directory: %w", err)
 	}
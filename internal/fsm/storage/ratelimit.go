@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/ratelimit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// RateLimitCollection holds the buckets of ratelimit.Limiter shared by every replica, keyed by group and user,
+// so it is not scoped by tenant. Buckets expire once they are full again.
+const RateLimitCollection = "ratelimits"
+
+type RateLimitStorage struct {
+	Storage
+}
+
+func NewRateLimitStorage(s *Storage) *RateLimitStorage {
+	return &RateLimitStorage{*s}
+}
+
+// Take refills the bucket and removes a token in a single update, so concurrent requests of the replicas
+// never take the same token.
+func (s *RateLimitStorage) Take(ctx context.Context, key string, rate ratelimit.Rate, now time.Time) (ratelimit.Result, error) {
+	ctx, end := s.observe(ctx, "RateLimitStorage.Take")
+	defer end()
+
+	db := s.db
+
+	burst := float64(rate.Burst)
+	elapsed := bson.D{{"$max", bson.A{
+		0,
+		bson.D{{"$divide", bson.A{bson.D{{"$subtract", bson.A{now, bson.D{{"$ifNull", bson.A{"$updatedAt", now}}}}}}, 1000}}},
+	}}}
+	refilled := bson.D{{"$min", bson.A{
+		burst,
+		bson.D{{"$add", bson.A{
+			bson.D{{"$ifNull", bson.A{"$tokens", burst}}},
+			bson.D{{"$multiply", bson.A{elapsed, rate.Sustained}}},
+		}}},
+	}}}
+	allowed := bson.D{{"$gte", bson.A{"$tokens", 1}}}
+
+	update := bson.A{
+		bson.D{{"$set", bson.D{{"tokens", refilled}}}},
+		bson.D{{"$set", bson.D{
+			{"allowed", allowed},
+			{"tokens", bson.D{{"$cond", bson.A{allowed, bson.D{{"$subtract", bson.A{"$tokens", 1}}}, "$tokens"}}}},
+			{"updatedAt", now},
+			{"expiresAt", now.Add(rate.Refill())},
+		}}},
+	}
+
+	var bucket struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	err := db.Collection(RateLimitCollection).
+		FindOneAndUpdate(ctx, bson.D{{"_id", key}}, update, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).
+		Decode(&bucket)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("unable to take token: %w", err)
+	}
+
+	if bucket.Allowed {
+		return ratelimit.Result{Allowed: true}, nil
+	}
+
+	return ratelimit.Result{
+		RetryAfter: time.Duration((1 - bucket.Tokens) / rate.Sustained * float64(time.Second)),
+	}, nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/metrics"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TestStaleRevisionChangesNothing checks that a write with a stale If-Match fails on the target document,
+// before the parent, the embedded copies or the sizes of the ancestors are touched.
+func TestStaleRevisionChangesNothing(t *testing.T) {
+	var (
+		id       = types.ObjectId("65f000000000000000000001")
+		parentID = types.ObjectId("65f000000000000000000002")
+		toID     = types.ObjectId("65f000000000000000000003")
+	)
+	file := bson.D{{"_id", id}, {"parentDirectoryID", string(parentID)}, {"size", 10}, {"revision", 4}}
+	dir := bson.D{{"_id", id}, {"parentDirectoryID", string(parentID)}, {"size", 10}, {"revision", 4}}
+	parent := bson.D{{"_id", parentID}, {"path", bson.A{}}}
+	to := bson.D{{"_id", toID}, {"path", bson.A{}}}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, c := range []struct {
+		name  string
+		reads []bson.D
+		write func(s *Storage, ctx context.Context) error
+		// commands is the conditional write followed by the read telling the revision changed
+		commands []string
+	}{
+		{
+			name:     "delete file",
+			reads:    []bson.D{file, parent},
+			write:    func(s *Storage, ctx context.Context) error { return NewFileStorage(s).Delete(ctx, id) },
+			commands: []string{"delete", "find"},
+		},
+		{
+			name:     "move file",
+			reads:    []bson.D{file, to, file, parent, to},
+			write:    func(s *Storage, ctx context.Context) error { return NewFileStorage(s).Move(ctx, id, toID) },
+			commands: []string{"findAndModify", "find"},
+		},
+		{
+			name:     "delete directory",
+			reads:    []bson.D{dir},
+			write:    func(s *Storage, ctx context.Context) error { return NewDirectoryStorage(s).Delete(ctx, id) },
+			commands: []string{"delete", "find"},
+		},
+		{
+			name:     "move directory",
+			reads:    []bson.D{dir, to, dir, parent, to},
+			write:    func(s *Storage, ctx context.Context) error { return NewDirectoryStorage(s).Move(ctx, id, toID) },
+			commands: []string{"findAndModify", "find"},
+		},
+	} {
+		mt.Run(c.name, func(mt *mtest.T) {
+			s := &Storage{db: mt.DB, m: metrics.New(), t: noop.NewTracerProvider().Tracer("")}
+			ctx := owncontext.WithPrecondition(owncontext.New(context.Background(), "user"), string(id), 3)
+
+			for _, document := range c.reads {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "fsm.documents", mtest.FirstBatch, document))
+			}
+			switch c.commands[0] {
+			case "delete":
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{"n", 0}))
+			default:
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{"value", nil}))
+			}
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "fsm.documents", mtest.FirstBatch, bson.D{{"revision", 4}}))
+
+			require.ErrorIs(mt, c.write(s, ctx), core.ErrRevisionMismatch)
+
+			var commands []string
+			for _, event := range mt.GetAllStartedEvents()[len(c.reads):] {
+				commands = append(commands, event.CommandName)
+			}
+			require.Equal(mt, c.commands, commands)
+		})
+	}
+}
+
+func TestEmbeddedCopiesGetRevision(t *testing.T) {
+	id := types.ObjectId("65f000000000000000000001")
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("star file", func(mt *mtest.T) {
+		s := &Storage{db: mt.DB, m: metrics.New(), t: noop.NewTracerProvider().Tracer("")}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{"value", bson.D{{"_id", id}, {"revision", 5}}}),
+			mtest.CreateSuccessResponse(bson.E{"n", 1}, bson.E{"nModified", 1}),
+		)
+
+		require.NoError(mt, NewFileStorage(s).Star(owncontext.New(context.Background(), "user"), id, true))
+
+		update := mt.GetAllStartedEvents()[1].Command.Lookup("updates", "0", "u", "$set")
+		require.Equal(mt, int64(5), update.Document().Lookup("files.$.revision").AsInt64())
+	})
+}
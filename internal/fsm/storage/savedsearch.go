@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const SavedSearchCollection = "savedSearches"
+
+type SavedSearchStorage struct {
+	Storage
+}
+
+func NewSavedSearchStorage(s *Storage) *SavedSearchStorage {
+	return &SavedSearchStorage{*s}
+}
+
+func (s *SavedSearchStorage) Get(ctx context.Context, id types.ObjectId) (*core.SavedSearch, error) {
+	ctx, end := s.observe(ctx, "SavedSearchStorage.Get")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+
+	var search core.SavedSearch
+	err := s.collection(ctx, SavedSearchCollection).
+		FindOne(ctx, filter).
+		Decode(&search)
+
+	return &search, err
+}
+
+func (s *SavedSearchStorage) List(ctx context.Context, userID string) ([]core.SavedSearch, error) {
+	ctx, end := s.observe(ctx, "SavedSearchStorage.List")
+	defer end()
+
+	filter := bson.D{{"userID", userID}}
+	cursor, err := s.collection(ctx, SavedSearchCollection).
+		Find(ctx, filter, options.Find().SetSort(bson.D{{"name", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find saved searches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	searches := []core.SavedSearch{}
+	if err := cursor.All(ctx, &searches); err != nil {
+		return nil, fmt.Errorf("unable to decode saved searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+func (s *SavedSearchStorage) Create(ctx context.Context, search *core.SavedSearch) (*core.SavedSearch, error) {
+	ctx, end := s.observe(ctx, "SavedSearchStorage.Create")
+	defer end()
+
+	search.TenantID = tenantOf(ctx)
+	search.CreatedAt = time.Now()
+	search.UpdatedAt = search.CreatedAt
+
+	result, err := s.collection(ctx, SavedSearchCollection).
+		InsertOne(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert saved search: %w", err)
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert id %v to object id", result.InsertedID)
+	}
+	search.ID = types.ObjectId(id.Hex())
+
+	return search, nil
+}
+
+func (s *SavedSearchStorage) Update(ctx context.Context, search *core.SavedSearch) error {
+	ctx, end := s.observe(ctx, "SavedSearchStorage.Update")
+	defer end()
+
+	search.UpdatedAt = time.Now()
+
+	filter := bson.D{{"_id", search.ID}}
+	update := bson.D{{"$set", bson.D{
+		{"name", search.Name},
+		{"query", search.Query},
+		{"filter", search.Filter},
+		{"sortByField", search.SortByField},
+		{"sortOrder", search.SortOrder},
+		{"updatedAt", search.UpdatedAt},
+	}}}
+	_, err := s.collection(ctx, SavedSearchCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update saved search: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SavedSearchStorage) Delete(ctx context.Context, id types.ObjectId) error {
+	ctx, end := s.observe(ctx, "SavedSearchStorage.Delete")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+	_, err := s.collection(ctx, SavedSearchCollection).DeleteOne(ctx, filter)
+
+	return err
+}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"slices"
+)
+
+const (
+	tenantField   = "tenantID"
+	revisionField = "revision"
+)
+
+// revisedCollections are the collections whose documents have a revision, see core.File.Revision.
+var revisedCollections = map[string]bool{
+	DirectoryCollection: true,
+	FileCollection:      true,
+}
+
+// collection limits every operation on a tenant owned collection to the tenant of the request, see owncontext.TenantID.
+// Documents inserted through it must carry the tenant themselves.
+// On revisedCollections it increments the revision of every document it changes,
+// and the first write to the document of the precondition of the request is conditional, see owncontext.WithPrecondition.
+type collection struct {
+	c            *mongo.Collection
+	tenantID     string
+	scoped       bool
+	revised      bool
+	precondition *owncontext.Precondition
+}
+
+func scoped(ctx context.Context, c *mongo.Collection) *collection {
+	tenantID, ok := owncontext.TenantID(ctx)
+
+	return &collection{
+		c:            c,
+		tenantID:     tenantID,
+		scoped:       ok,
+		revised:      c != nil && revisedCollections[c.Name()],
+		precondition: owncontext.PreconditionOf(ctx),
+	}
+}
+
+func (s *Storage) collection(ctx context.Context, name string) *collection {
+	return scoped(ctx, s.db.Collection(name))
+}
+
+// updateRevised applies update to the document of a revisedCollections collection and returns the revision it got,
+// which the embedded copies of the document are set to, so that listings show the same ETag.
+func (s *Storage) updateRevised(ctx context.Context, name string, id types.ObjectId, update bson.D) (uint64, error) {
+	var document struct {
+		Revision uint64 `bson:"revision"`
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.D{{revisionField, 1}})
+	err := s.collection(ctx, name).
+		FindOneAndUpdate(ctx, bson.D{{"_id", id}}, update, opts).
+		Decode(&document)
+
+	return document.Revision, err
+}
+
+// tenantOf returns the tenant documents created with ctx belong to.
+func tenantOf(ctx context.Context) string {
+	tenantID, _ := owncontext.TenantID(ctx)
+
+	return tenantID
+}
+
+// tenantFilter adds the tenant of ctx to filter, it is used for pipelines the collection does not see, e.g. $unionWith.
+func tenantFilter(ctx context.Context, filter bson.D) bson.D {
+	tenantID, ok := owncontext.TenantID(ctx)
+	if !ok || filter == nil {
+		return filter
+	}
+
+	return append(slices.Clip(filter), bson.E{tenantField, tenantID})
+}
+
+func (c *collection) filter(filter bson.D) bson.D {
+	if !c.scoped {
+		return filter
+	}
+
+	return append(slices.Clip(filter), bson.E{tenantField, c.tenantID})
+}
+
+// pipeline merges the tenant into the leading $match, which keeps a $text match the first stage.
+func (c *collection) pipeline(pipeline any) any {
+	if !c.scoped {
+		return pipeline
+	}
+
+	switch pipeline := pipeline.(type) {
+	case []bson.D:
+		return c.pipelineD(pipeline)
+	case mongo.Pipeline:
+		return mongo.Pipeline(c.pipelineD(pipeline))
+	case []bson.M:
+		return append([]bson.M{{"$match": bson.D{{tenantField, c.tenantID}}}}, pipeline...)
+	default:
+		panic("unsupported pipeline type")
+	}
+}
+
+func (c *collection) pipelineD(pipeline []bson.D) []bson.D {
+	if len(pipeline) > 0 && len(pipeline[0]) == 1 && pipeline[0][0].Key == "$match" {
+		if match, ok := pipeline[0][0].Value.(bson.D); ok {
+			pipeline = slices.Clone(pipeline)
+			pipeline[0] = bson.D{{"$match", c.filter(match)}}
+
+			return pipeline
+		}
+	}
+
+	return append([]bson.D{{{"$match", bson.D{{tenantField, c.tenantID}}}}}, pipeline...)
+}
+
+// revise adds the increment of the revision to update on revisedCollections.
+func (c *collection) revise(update any) (any, error) {
+	if !c.revised {
+		return update, nil
+	}
+
+	return revised(update)
+}
+
+// revised adds the increment of the revision to update, merging it into an existing $inc.
+func revised(update any) (any, error) {
+	switch update := update.(type) {
+	case bson.D:
+		for i, e := range update {
+			if inc, ok := e.Value.(bson.D); ok && e.Key == "$inc" {
+				update = slices.Clone(update)
+				update[i].Value = append(slices.Clip(inc), bson.E{revisionField, 1})
+
+				return update, nil
+			}
+		}
+
+		return append(slices.Clip(update), bson.E{"$inc", bson.D{{revisionField, 1}}}), nil
+	case []bson.D:
+		revision := bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$" + revisionField, 0}}}, 1}}}
+
+		return append(slices.Clip(update), bson.D{{"$set", bson.D{{revisionField, revision}}}}), nil
+	default:
+		return nil, fmt.Errorf("unsupported update type %T", update)
+	}
+}
+
+// conditional adds the revision of the precondition of the request to filter, if filter selects the document of the
+// precondition and no earlier write of the request was conditional, see owncontext.WithPrecondition.
+func (c *collection) conditional(filter bson.D) (bson.D, bool) {
+	p := c.precondition
+	if !c.revised || p == nil || p.Applied || len(filter) == 0 || filter[0].Key != "_id" {
+		return filter, false
+	}
+	if id, ok := filter[0].Value.(types.ObjectId); !ok || string(id) != p.ID {
+		return filter, false
+	}
+	p.Applied = true
+
+	// documents written before revisions existed have none
+	var revision any = p.Revision
+	if p.Revision == 0 {
+		revision = bson.D{{"$in", bson.A{0, nil}}}
+	}
+
+	return append(slices.Clip(filter), bson.E{revisionField, revision}), true
+}
+
+// changed reports whether the document selected by filter exists with another revision than the precondition,
+// it tells a conditional write which matched nothing because of the revision from one which would not have matched anyway.
+func (c *collection) changed(ctx context.Context, filter bson.D) bool {
+	var document struct {
+		Revision uint64 `bson:"revision"`
+	}
+	err := c.c.FindOne(ctx, c.filter(filter), options.FindOne().SetProjection(bson.D{{revisionField, 1}})).
+		Decode(&document)
+
+	return err == nil && document.Revision != c.precondition.Revision
+}
+
+func (c *collection) FindOne(ctx context.Context, filter bson.D, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	return c.c.FindOne(ctx, c.filter(filter), opts...)
+}
+
+func (c *collection) Find(ctx context.Context, filter bson.D, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return c.c.Find(ctx, c.filter(filter), opts...)
+}
+
+func (c *collection) Aggregate(ctx context.Context, pipeline any, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return c.c.Aggregate(ctx, c.pipeline(pipeline), opts...)
+}
+
+func (c *collection) FindOneAndUpdate(ctx context.Context, filter bson.D, update any, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	update, err := c.revise(update)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+
+	conditional, ok := c.conditional(filter)
+	result := c.c.FindOneAndUpdate(ctx, c.filter(conditional), update, opts...)
+	if ok && errors.Is(result.Err(), mongo.ErrNoDocuments) && c.changed(ctx, filter) {
+		return mongo.NewSingleResultFromDocument(bson.D{}, core.ErrRevisionMismatch, nil)
+	}
+
+	return result
+}
+
+func (c *collection) FindOneAndDelete(ctx context.Context, filter bson.D, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
+	conditional, ok := c.conditional(filter)
+	result := c.c.FindOneAndDelete(ctx, c.filter(conditional), opts...)
+	if ok && errors.Is(result.Err(), mongo.ErrNoDocuments) && c.changed(ctx, filter) {
+		return mongo.NewSingleResultFromDocument(bson.D{}, core.ErrRevisionMismatch, nil)
+	}
+
+	return result
+}
+
+func (c *collection) InsertOne(ctx context.Context, document any, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	return c.c.InsertOne(ctx, document, opts...)
+}
+
+func (c *collection) UpdateOne(ctx context.Context, filter bson.D, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	update, err := c.revise(update)
+	if err != nil {
+		return nil, err
+	}
+
+	conditional, ok := c.conditional(filter)
+	result, err := c.c.UpdateOne(ctx, c.filter(conditional), update, opts...)
+	if err == nil && ok && result.MatchedCount == 0 && c.changed(ctx, filter) {
+		return nil, core.ErrRevisionMismatch
+	}
+
+	return result, err
+}
+
+func (c *collection) UpdateMany(ctx context.Context, filter bson.D, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	update, err := c.revise(update)
+	if err != nil {
+		return nil, err
+	}
+
+	conditional, ok := c.conditional(filter)
+	result, err := c.c.UpdateMany(ctx, c.filter(conditional), update, opts...)
+	if err == nil && ok && result.MatchedCount == 0 && c.changed(ctx, filter) {
+		return nil, core.ErrRevisionMismatch
+	}
+
+	return result, err
+}
+
+func (c *collection) DeleteOne(ctx context.Context, filter bson.D, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	conditional, ok := c.conditional(filter)
+	result, err := c.c.DeleteOne(ctx, c.filter(conditional), opts...)
+	if err == nil && ok && result.DeletedCount == 0 && c.changed(ctx, filter) {
+		return nil, core.ErrRevisionMismatch
+	}
+
+	return result, err
+}
+
+func (c *collection) DeleteMany(ctx context.Context, filter bson.D, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return c.c.DeleteMany(ctx, c.filter(filter), opts...)
+}
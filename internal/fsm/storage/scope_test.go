@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/StratuStore/fsm/internal/libs/owncontext"
+	"github.com/mbretter/go-mongodb/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestCollectionScopesToTenant(t *testing.T) {
+	ctx := owncontext.NewWithIdentity(context.Background(), owncontext.Identity{UserID: "user", TenantID: "acme"})
+	c := scoped(ctx, nil)
+
+	filter := bson.D{{"_id", "1"}}
+	require.Equal(t, bson.D{{"_id", "1"}, {tenantField, "acme"}}, c.filter(filter))
+	require.Equal(t, bson.D{{"_id", "1"}}, filter)
+
+	text := bson.D{{"$match", bson.D{{"$text", bson.D{{"$search", "report"}}}}}}
+	require.Equal(t, []bson.D{
+		{{"$match", bson.D{{"$text", bson.D{{"$search", "report"}}}, {tenantField, "acme"}}}},
+	}, c.pipeline([]bson.D{text}))
+
+	sort := bson.D{{"$sort", bson.D{{"name", 1}}}}
+	require.Equal(t, []bson.D{
+		{{"$match", bson.D{{tenantField, "acme"}}}},
+		sort,
+	}, c.pipeline([]bson.D{sort}))
+
+	require.Equal(t, mongo.Pipeline{
+		{{"$match", bson.D{{"userID", "user"}, {tenantField, "acme"}}}},
+	}, c.pipeline(mongo.Pipeline{{{"$match", bson.D{{"userID", "user"}}}}}))
+}
+
+func TestCollectionUnscoped(t *testing.T) {
+	filter := bson.D{{"_id", "1"}}
+
+	require.Equal(t, filter, scoped(context.Background(), nil).filter(filter))
+
+	ctx := owncontext.Unscoped(owncontext.New(context.Background(), "fs"))
+	require.Equal(t, filter, scoped(ctx, nil).filter(filter))
+	require.Equal(t, filter, tenantFilter(ctx, filter))
+}
+
+func TestRevised(t *testing.T) {
+	update, err := revised(bson.D{{"$set", bson.D{{"name", "a"}}}})
+	require.NoError(t, err)
+	require.Equal(t, bson.D{
+		{"$set", bson.D{{"name", "a"}}},
+		{"$inc", bson.D{{revisionField, 1}}},
+	}, update)
+
+	update, err = revised(bson.D{{"$inc", bson.D{{"size", 10}}}})
+	require.NoError(t, err)
+	require.Equal(t, bson.D{
+		{"$inc", bson.D{{"size", 10}, {revisionField, 1}}},
+	}, update)
+
+	update, err = revised([]bson.D{{{"$set", bson.D{{"locks", bson.A{}}}}}})
+	require.NoError(t, err)
+	require.Len(t, update, 2)
+
+	_, err = revised(bson.M{"$set": bson.M{"name": "a"}})
+	require.Error(t, err)
+}
+
+// revisedScope returns the collection of ctx as it is for revisedCollections.
+func revisedScope(ctx context.Context) *collection {
+	c := scoped(ctx, nil)
+	c.revised = true
+
+	return c
+}
+
+func TestConditional(t *testing.T) {
+	id := types.ObjectId("65f000000000000000000001")
+	ctx := owncontext.WithPrecondition(owncontext.New(context.Background(), "user"), string(id), 3)
+	c := revisedScope(ctx)
+
+	filter, ok := c.conditional(bson.D{{"files._id", id}})
+	require.False(t, ok)
+	require.Equal(t, bson.D{{"files._id", id}}, filter)
+
+	filter, ok = c.conditional(bson.D{{"_id", id}})
+	require.True(t, ok)
+	require.Equal(t, bson.D{{"_id", id}, {revisionField, uint64(3)}}, filter)
+
+	// only the first write to the document is conditional, the later ones see the revision it incremented
+	_, ok = revisedScope(ctx).conditional(bson.D{{"_id", id}})
+	require.False(t, ok)
+
+	ctx = owncontext.WithPrecondition(owncontext.New(context.Background(), "user"), string(id), 0)
+	filter, ok = revisedScope(ctx).conditional(bson.D{{"_id", id}})
+	require.True(t, ok)
+	require.Equal(t, bson.D{{"_id", id}, {revisionField, bson.D{{"$in", bson.A{0, nil}}}}}, filter)
+
+	_, ok = revisedScope(context.Background()).conditional(bson.D{{"_id", id}})
+	require.False(t, ok)
+
+	// collections without revisions are never conditional
+	_, ok = scoped(ctx, nil).conditional(bson.D{{"_id", id}})
+	require.False(t, ok)
+}
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/StratuStore/fsm/internal/fsm/search"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const ShortcutCollection = "shortcuts"
+
+type ShortcutStorage struct {
+	Storage
+}
+
+func NewShortcutStorage(s *Storage) *ShortcutStorage {
+	return &ShortcutStorage{*s}
+}
+
+func (s *ShortcutStorage) Get(ctx context.Context, id types.ObjectId) (*core.Shortcut, error) {
+	ctx, end := s.observe(ctx, "ShortcutStorage.Get")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+
+	var shortcut core.Shortcut
+	err := s.collection(ctx, ShortcutCollection).
+		FindOne(ctx, filter).
+		Decode(&shortcut)
+
+	return &shortcut, err
+}
+
+// Create stores the shortcut and embeds it into its parent directory. The size of the parent is left as is.
+func (s *ShortcutStorage) Create(ctx context.Context, shortcut *core.Shortcut) (*core.Shortcut, error) {
+	ctx, end := s.observe(ctx, "ShortcutStorage.Create")
+	defer end()
+
+	shortcut.TenantID = tenantOf(ctx)
+	shortcut.CreatedAt = time.Now()
+	shortcut.UpdatedAt = shortcut.CreatedAt
+
+	result, err := s.collection(ctx, ShortcutCollection).
+		InsertOne(ctx, shortcut)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert shortcut: %w", err)
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert id %v to object id", result.InsertedID)
+	}
+	shortcut.ID = types.ObjectId(id.Hex())
+
+	filter := bson.D{{"_id", types.ObjectId(shortcut.ParentDirectoryID)}}
+	update := bson.D{{"$push", bson.D{{"shortcuts", shortcut}}}, {"$inc", bson.D{{"shortcutsCount", 1}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert shortcut into dir: %w", err)
+	}
+
+	return shortcut, nil
+}
+
+func (s *ShortcutStorage) Rename(ctx context.Context, id types.ObjectId, newName string) error {
+	ctx, end := s.observe(ctx, "ShortcutStorage.Rename")
+	defer end()
+
+	timestamp := time.Now()
+
+	filter := bson.D{{"_id", id}}
+	update := bson.D{{"$set", bson.D{{"name", newName}, {"updatedAt", timestamp}}}}
+	_, err := s.collection(ctx, ShortcutCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update name of the shortcut: %w", err)
+	}
+
+	filter = bson.D{{"shortcuts._id", id}}
+	update = bson.D{{"$set", bson.D{{"shortcuts.$.name", newName}, {"shortcuts.$.updatedAt", timestamp}}}}
+	_, err = s.collection(ctx, DirectoryCollection).
+		UpdateMany(
+			ctx,
+			filter,
+			update,
+		)
+
+	return err
+}
+
+func (s *ShortcutStorage) Delete(ctx context.Context, id types.ObjectId) error {
+	ctx, end := s.observe(ctx, "ShortcutStorage.Delete")
+	defer end()
+
+	filter := bson.D{{"shortcuts._id", id}}
+	update := bson.D{
+		{"$pull", bson.D{{"shortcuts", bson.D{{"_id", id}}}}},
+		{"$inc", bson.D{{"shortcutsCount", -1}}},
+	}
+	_, err := s.collection(ctx, DirectoryCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to delete shortcut from parent: %w", err)
+	}
+
+	filter = bson.D{{"_id", id}}
+	_, err = s.collection(ctx, ShortcutCollection).DeleteOne(ctx, filter)
+
+	return err
+}
+
+// StupidDeleteShortcuts removes the shortcuts stored inside the directory without touching the directory itself.
+func (s *Storage) StupidDeleteShortcuts(ctx context.Context, parentID types.ObjectId) error {
+	ctx, end := s.observe(ctx, "Storage.StupidDeleteShortcuts")
+	defer end()
+
+	filter := bson.D{{"parentDirectoryID", string(parentID)}}
+	_, err := s.collection(ctx, ShortcutCollection).DeleteMany(ctx, filter)
+
+	return err
+}
+
+// GetShortcutTargets returns the directories and files with the given IDs keyed by ID,
+// without their entries. Missing IDs are absent from the result.
+func (s *Storage) GetShortcutTargets(
+	ctx context.Context,
+	directoryIDs, fileIDs []types.ObjectId,
+) (map[types.ObjectId]*core.Directory, map[types.ObjectId]*core.File, error) {
+	ctx, end := s.observe(ctx, "Storage.GetShortcutTargets")
+	defer end()
+
+	directories := make(map[types.ObjectId]*core.Directory, len(directoryIDs))
+	if len(directoryIDs) != 0 {
+		filter := bson.D{{"_id", bson.D{{"$in", directoryIDs}}}}
+		projection := bson.D{{"directories", 0}, {"files", 0}, {"shortcuts", 0}, {search.KeywordsField, 0}}
+		cursor, err := s.collection(ctx, DirectoryCollection).
+			Find(ctx, filter, options.Find().SetProjection(projection))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to find target directories: %w", err)
+		}
+		var result []core.Directory
+		if err := cursor.All(ctx, &result); err != nil {
+			return nil, nil, fmt.Errorf("unable to decode target directories: %w", err)
+		}
+		for i := range result {
+			directories[result[i].ID] = &result[i]
+		}
+	}
+
+	files := make(map[types.ObjectId]*core.File, len(fileIDs))
+	if len(fileIDs) != 0 {
+		filter := bson.D{{"_id", bson.D{{"$in", fileIDs}}}}
+		projection := bson.D{{search.KeywordsField, 0}}
+		cursor, err := s.collection(ctx, FileCollection).
+			Find(ctx, filter, options.Find().SetProjection(projection))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to find target files: %w", err)
+		}
+		var result []core.File
+		if err := cursor.All(ctx, &result); err != nil {
+			return nil, nil, fmt.Errorf("unable to decode target files: %w", err)
+		}
+		for i := range result {
+			files[result[i].ID] = &result[i]
+		}
+	}
+
+	return directories, files, nil
+}
@@ -17,7 +17,7 @@ func IncrementSizes(db *mongo.Database, ctx context.Context, path []core.PathEle
 
 	filter := bson.D{{"_id", bson.D{{"$in", toDirIDs}}}}
 	update := bson.D{{"$inc", bson.D{{"size", size}}}}
-	_, err := db.Collection(DirectoryCollection).
+	_, err := scoped(ctx, db.Collection(DirectoryCollection)).
 		UpdateMany(
 			ctx,
 			filter,
@@ -29,7 +29,7 @@ func IncrementSizes(db *mongo.Database, ctx context.Context, path []core.PathEle
 
 	filter = bson.D{{"directories._id", bson.D{{"$in", toDirIDs}}}}
 	update = bson.D{{"$inc", bson.D{{"directories.$.size", size}}}}
-	_, err = db.Collection(DirectoryCollection).
+	_, err = scoped(ctx, db.Collection(DirectoryCollection)).
 		UpdateMany(
 			ctx,
 			filter,
@@ -45,7 +45,7 @@ func IncrementSizes(db *mongo.Database, ctx context.Context, path []core.PathEle
 func UpdateEmbeddedSize(db *mongo.Database, ctx context.Context, id types.ObjectId, size int) error {
 	filter := bson.D{{"directories._id", id}}
 	update := bson.D{{"$inc", bson.D{{"directories.$.size", size}}}}
-	_, err := db.Collection(DirectoryCollection).
+	_, err := scoped(ctx, db.Collection(DirectoryCollection)).
 		UpdateMany(
 			ctx,
 			filter,
@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const TagCollection = "tags"
+
+type TagStorage struct {
+	Storage
+}
+
+func NewTagStorage(s *Storage) *TagStorage {
+	return &TagStorage{*s}
+}
+
+func (s *TagStorage) GetTag(ctx context.Context, id types.ObjectId) (*core.Tag, error) {
+	ctx, end := s.observe(ctx, "TagStorage.GetTag")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+
+	var tag core.Tag
+	err := s.collection(ctx, TagCollection).
+		FindOne(ctx, filter).
+		Decode(&tag)
+
+	return &tag, err
+}
+
+func (s *TagStorage) List(ctx context.Context, userID string) ([]core.Tag, error) {
+	ctx, end := s.observe(ctx, "TagStorage.List")
+	defer end()
+
+	filter := bson.D{{"userID", userID}}
+	cursor, err := s.collection(ctx, TagCollection).
+		Find(ctx, filter, options.Find().SetSort(bson.D{{"name", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tags := []core.Tag{}
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, fmt.Errorf("unable to decode tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (s *TagStorage) Create(ctx context.Context, userID, name, color string) (*core.Tag, error) {
+	ctx, end := s.observe(ctx, "TagStorage.Create")
+	defer end()
+
+	tag := core.Tag{
+		UserID:    userID,
+		TenantID:  tenantOf(ctx),
+		Name:      name,
+		Color:     color,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	result, err := s.collection(ctx, TagCollection).
+		InsertOne(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert tag: %w", err)
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert id %v to object id", result.InsertedID)
+	}
+	tag.ID = types.ObjectId(id.Hex())
+
+	return &tag, nil
+}
+
+// Update changes the tag and every copy of it embedded in directories and files.
+func (s *TagStorage) Update(ctx context.Context, id types.ObjectId, name, color string) error {
+	ctx, end := s.observe(ctx, "TagStorage.Update")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+	update := bson.D{{"$set", bson.D{{"name", name}, {"color", color}, {"updatedAt", time.Now()}}}}
+	_, err := s.collection(ctx, TagCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update tag: %w", err)
+	}
+
+	filter = bson.D{{"tags._id", id}}
+	update = bson.D{{"$set", bson.D{{"tags.$.name", name}, {"tags.$.color", color}}}}
+	for _, collection := range []string{DirectoryCollection, FileCollection} {
+		_, err = s.collection(ctx, collection).
+			UpdateMany(
+				ctx,
+				filter,
+				update,
+			)
+		if err != nil {
+			return fmt.Errorf("unable to update tag inside %v: %w", collection, err)
+		}
+	}
+
+	arrayFilter := []any{bson.D{{"entry.tags._id", id}}, bson.D{{"tag._id", id}}}
+	for _, embedded := range []string{"directories", "files"} {
+		filter = bson.D{{embedded + ".tags._id", id}}
+		update = bson.D{{"$set", bson.D{
+			{embedded + ".$[entry].tags.$[tag].name", name},
+			{embedded + ".$[entry].tags.$[tag].color", color},
+		}}}
+		_, err = s.collection(ctx, DirectoryCollection).
+			UpdateMany(
+				ctx,
+				filter,
+				update,
+				options.Update().SetArrayFilters(options.ArrayFilters{Filters: arrayFilter}),
+			)
+		if err != nil {
+			return fmt.Errorf("unable to update tag inside embedded %v: %w", embedded, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the tag and detaches it from every directory and file.
+func (s *TagStorage) Delete(ctx context.Context, id types.ObjectId) error {
+	ctx, end := s.observe(ctx, "TagStorage.Delete")
+	defer end()
+
+	filter := bson.D{{"tags._id", id}}
+	update := bson.D{{"$pull", bson.D{{"tags", bson.D{{"_id", id}}}}}}
+	for _, collection := range []string{DirectoryCollection, FileCollection} {
+		_, err := s.collection(ctx, collection).
+			UpdateMany(
+				ctx,
+				filter,
+				update,
+			)
+		if err != nil {
+			return fmt.Errorf("unable to detach tag inside %v: %w", collection, err)
+		}
+	}
+
+	arrayFilter := []any{bson.D{{"entry.tags._id", id}}}
+	for _, embedded := range []string{"directories", "files"} {
+		filter = bson.D{{embedded + ".tags._id", id}}
+		update = bson.D{{"$pull", bson.D{{embedded + ".$[entry].tags", bson.D{{"_id", id}}}}}}
+		_, err := s.collection(ctx, DirectoryCollection).
+			UpdateMany(
+				ctx,
+				filter,
+				update,
+				options.Update().SetArrayFilters(options.ArrayFilters{Filters: arrayFilter}),
+			)
+		if err != nil {
+			return fmt.Errorf("unable to detach tag inside embedded %v: %w", embedded, err)
+		}
+	}
+
+	filter = bson.D{{"_id", id}}
+	_, err := s.collection(ctx, TagCollection).DeleteOne(ctx, filter)
+
+	return err
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const TenantCollection = "tenants"
+
+type TenantStorage struct {
+	Storage
+}
+
+func NewTenantStorage(s *Storage) *TenantStorage {
+	return &TenantStorage{*s}
+}
+
+// GetTenant returns the configuration of the tenant, core.DefaultTenant if it has none.
+func (s *Storage) GetTenant(ctx context.Context, id string) (*core.Tenant, error) {
+	ctx, end := s.observe(ctx, "Storage.GetTenant")
+	defer end()
+
+	db := s.db
+
+	filter := bson.D{{"_id", id}}
+
+	var tenant core.Tenant
+	err := db.Collection(TenantCollection).
+		FindOne(ctx, filter).
+		Decode(&tenant)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return core.DefaultTenant(id), nil
+	}
+
+	return &tenant, err
+}
+
+// GetRootSize returns the size of the tree of the owner, 0 if it has no root yet.
+func (s *Storage) GetRootSize(ctx context.Context, owner string) (uint, error) {
+	ctx, end := s.observe(ctx, "Storage.GetRootSize")
+	defer end()
+
+	filter := bson.D{{"userID", owner}, {"path", nil}}
+
+	var root core.Directory
+	err := s.collection(ctx, DirectoryCollection).
+		FindOne(ctx, filter, options.FindOne().SetProjection(bson.D{{"size", 1}})).
+		Decode(&root)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+
+	return root.Size, err
+}
+
+func (s *TenantStorage) List(ctx context.Context) ([]core.Tenant, error) {
+	ctx, end := s.observe(ctx, "TenantStorage.List")
+	defer end()
+
+	db := s.db
+
+	cursor, err := db.Collection(TenantCollection).
+		Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find tenants: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tenants := []core.Tenant{}
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, fmt.Errorf("unable to decode tenants: %w", err)
+	}
+
+	return tenants, nil
+}
+
+// Put creates or replaces the configuration of the tenant.
+func (s *TenantStorage) Put(ctx context.Context, tenant *core.Tenant) (*core.Tenant, error) {
+	ctx, end := s.observe(ctx, "TenantStorage.Put")
+	defer end()
+
+	db := s.db
+
+	filter := bson.D{{"_id", tenant.ID}}
+	update := bson.D{
+		{"$set", bson.D{
+			{"name", tenant.Name},
+			{"userQuota", tenant.UserQuota},
+			{"publicSharing", tenant.PublicSharing},
+			{"workspaces", tenant.Workspaces},
+			{"updatedAt", time.Now()},
+		}},
+		{"$setOnInsert", bson.D{{"createdAt", time.Now()}}},
+	}
+
+	var result core.Tenant
+	err := db.Collection(TenantCollection).
+		FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).
+		Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("unable to put tenant: %w", err)
+	}
+
+	return &result, nil
+}
+
+// Delete removes the configuration, the tenant falls back to core.DefaultTenant. Its data is kept.
+func (s *TenantStorage) Delete(ctx context.Context, id string) error {
+	ctx, end := s.observe(ctx, "TenantStorage.Delete")
+	defer end()
+
+	db := s.db
+
+	filter := bson.D{{"_id", id}}
+	_, err := db.Collection(TenantCollection).DeleteOne(ctx, filter)
+
+	return err
+}
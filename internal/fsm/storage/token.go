@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// APITokenCollection holds the API tokens, they are removed once expired.
+const APITokenCollection = "apiTokens"
+
+type APITokenStorage struct {
+	Storage
+}
+
+func NewAPITokenStorage(s *Storage) *APITokenStorage {
+	return &APITokenStorage{*s}
+}
+
+func (s *APITokenStorage) Create(ctx context.Context, token *core.APIToken) (*core.APIToken, error) {
+	ctx, end := s.observe(ctx, "APITokenStorage.Create")
+	defer end()
+
+	token.TenantID = tenantOf(ctx)
+	token.CreatedAt = time.Now()
+
+	result, err := s.collection(ctx, APITokenCollection).
+		InsertOne(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert api token: %w", err)
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert id %v to object id", result.InsertedID)
+	}
+	token.ID = types.ObjectId(id.Hex())
+
+	return token, nil
+}
+
+// List returns the tokens of the user, the latest first.
+func (s *APITokenStorage) List(ctx context.Context, userID string) ([]core.APIToken, error) {
+	ctx, end := s.observe(ctx, "APITokenStorage.List")
+	defer end()
+
+	filter := bson.D{{"userID", userID}}
+	cursor, err := s.collection(ctx, APITokenCollection).
+		Find(ctx, filter, options.Find().SetSort(bson.D{{"createdAt", -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find api tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []core.APIToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, fmt.Errorf("unable to decode api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Delete removes the token of the user and returns it.
+func (s *APITokenStorage) Delete(ctx context.Context, userID string, id types.ObjectId) (*core.APIToken, error) {
+	ctx, end := s.observe(ctx, "APITokenStorage.Delete")
+	defer end()
+
+	filter := bson.D{{"_id", id}, {"userID", userID}}
+
+	var token core.APIToken
+	err := s.collection(ctx, APITokenCollection).
+		FindOneAndDelete(ctx, filter).
+		Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// GetByHash returns the token with the hash of any tenant, the tenant is not known before the token is.
+func (s *APITokenStorage) GetByHash(ctx context.Context, hash string) (*core.APIToken, error) {
+	ctx, end := s.observe(ctx, "APITokenStorage.GetByHash")
+	defer end()
+
+	db := s.db
+
+	filter := bson.D{{"hash", hash}}
+
+	var token core.APIToken
+	err := db.Collection(APITokenCollection).
+		FindOne(ctx, filter).
+		Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Touch sets the time the token was last used.
+func (s *APITokenStorage) Touch(ctx context.Context, id types.ObjectId, at time.Time) error {
+	ctx, end := s.observe(ctx, "APITokenStorage.Touch")
+	defer end()
+
+	db := s.db
+
+	filter := bson.D{{"_id", id}}
+	update := bson.D{{"$set", bson.D{{"lastUsedAt", at}}}}
+	if _, err := db.Collection(APITokenCollection).UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("unable to update last use of api token: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Totals counts the stored files and directories, the bytes are the sum of the sizes of all roots.
+// The counts are estimated from the collection metadata.
+func (s *Storage) Totals(ctx context.Context) (*core.Totals, error) {
+	ctx, end := s.observe(ctx, "Storage.Totals")
+	defer end()
+
+	db := s.db
+
+	files, err := db.Collection(FileCollection).EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to count files: %w", err)
+	}
+	directories, err := db.Collection(DirectoryCollection).EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to count directories: %w", err)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"path", nil}}}},
+		{{"$group", bson.D{{"_id", nil}, {"bytes", bson.D{{"$sum", "$size"}}}}}},
+	}
+	cursor, err := db.Collection(DirectoryCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sum root sizes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sum struct {
+		Bytes uint `bson:"bytes"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&sum); err != nil {
+			return nil, fmt.Errorf("unable to decode root sizes: %w", err)
+		}
+	}
+
+	return &core.Totals{Files: uint(files), Directories: uint(directories), Bytes: sum.Bytes}, cursor.Err()
+}
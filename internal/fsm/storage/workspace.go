@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/fsm/core"
+	"github.com/mbretter/go-mongodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const WorkspaceCollection = "workspaces"
+
+type WorkspaceStorage struct {
+	Storage
+}
+
+func NewWorkspaceStorage(s *Storage) *WorkspaceStorage {
+	return &WorkspaceStorage{*s}
+}
+
+func (s *WorkspaceStorage) GetWorkspace(ctx context.Context, id types.ObjectId) (*core.Workspace, error) {
+	ctx, end := s.observe(ctx, "WorkspaceStorage.GetWorkspace")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+
+	var workspace core.Workspace
+	err := s.collection(ctx, WorkspaceCollection).
+		FindOne(ctx, filter).
+		Decode(&workspace)
+
+	return &workspace, err
+}
+
+// List returns the workspaces the user is a member of.
+func (s *WorkspaceStorage) List(ctx context.Context, userID string) ([]core.Workspace, error) {
+	ctx, end := s.observe(ctx, "WorkspaceStorage.List")
+	defer end()
+
+	filter := bson.D{{"members.userID", userID}}
+	cursor, err := s.collection(ctx, WorkspaceCollection).
+		Find(ctx, filter, options.Find().SetSort(bson.D{{"name", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find workspaces: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	workspaces := []core.Workspace{}
+	if err := cursor.All(ctx, &workspaces); err != nil {
+		return nil, fmt.Errorf("unable to decode workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// owners returns the owners of the items the user can read: the user and the workspaces the user is a member of.
+func (s *WorkspaceStorage) owners(ctx context.Context, userID string) ([]string, error) {
+	filter := bson.D{{"members.userID", userID}}
+	cursor, err := s.collection(ctx, WorkspaceCollection).
+		Find(ctx, filter, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find workspaces: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	owners := []string{userID}
+	for cursor.Next(ctx) {
+		var workspace core.Workspace
+		if err := cursor.Decode(&workspace); err != nil {
+			return nil, fmt.Errorf("unable to decode workspace: %w", err)
+		}
+		owners = append(owners, workspace.Owner())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("unable to iterate workspaces: %w", err)
+	}
+
+	return owners, nil
+}
+
+// Create inserts the workspace together with its root directory, which is owned by the workspace.
+func (s *WorkspaceStorage) Create(ctx context.Context, workspace *core.Workspace) (*core.Workspace, error) {
+	ctx, end := s.observe(ctx, "WorkspaceStorage.Create")
+	defer end()
+
+	workspace.ID = types.ObjectId(primitive.NewObjectID().Hex())
+	workspace.TenantID = tenantOf(ctx)
+	workspace.CreatedAt = time.Now()
+	workspace.UpdatedAt = time.Now()
+
+	root := core.Directory{
+		UserID:      workspace.Owner(),
+		TenantID:    tenantOf(ctx),
+		Path:        nil,
+		Name:        workspace.Name,
+		Keywords:    s.index.Keywords(workspace.Name, nil),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Directories: []core.Directory{},
+		Files:       []core.File{},
+	}
+
+	result, err := s.collection(ctx, DirectoryCollection).
+		InsertOne(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert workspace root: %w", err)
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert id %v to object id", result.InsertedID)
+	}
+	workspace.RootID = types.ObjectId(id.Hex())
+
+	if _, err := s.collection(ctx, WorkspaceCollection).InsertOne(ctx, workspace); err != nil {
+		return nil, fmt.Errorf("unable to insert workspace: %w", err)
+	}
+
+	return workspace, nil
+}
+
+func (s *WorkspaceStorage) Update(ctx context.Context, id types.ObjectId, name string, quota uint) error {
+	ctx, end := s.observe(ctx, "WorkspaceStorage.Update")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+	update := bson.D{{"$set", bson.D{{"name", name}, {"quota", quota}, {"updatedAt", time.Now()}}}}
+	_, err := s.collection(ctx, WorkspaceCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update workspace: %w", err)
+	}
+
+	return nil
+}
+
+// SetMember adds the member or replaces the role of an existing one.
+func (s *WorkspaceStorage) SetMember(ctx context.Context, id types.ObjectId, member core.Member) error {
+	ctx, end := s.observe(ctx, "WorkspaceStorage.SetMember")
+	defer end()
+
+	filter := bson.D{{"_id", id}, {"members.userID", member.UserID}}
+	update := bson.D{{"$set", bson.D{{"members.$.role", member.Role}, {"updatedAt", time.Now()}}}}
+	result, err := s.collection(ctx, WorkspaceCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to update member: %w", err)
+	}
+	if result.MatchedCount != 0 {
+		return nil
+	}
+
+	filter = bson.D{{"_id", id}}
+	update = bson.D{{"$push", bson.D{{"members", member}}}, {"$set", bson.D{{"updatedAt", time.Now()}}}}
+	_, err = s.collection(ctx, WorkspaceCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to add member: %w", err)
+	}
+
+	return nil
+}
+
+func (s *WorkspaceStorage) RemoveMember(ctx context.Context, id types.ObjectId, userID string) error {
+	ctx, end := s.observe(ctx, "WorkspaceStorage.RemoveMember")
+	defer end()
+
+	filter := bson.D{{"_id", id}}
+	update := bson.D{
+		{"$pull", bson.D{{"members", bson.D{{"userID", userID}}}}},
+		{"$set", bson.D{{"updatedAt", time.Now()}}},
+	}
+	_, err := s.collection(ctx, WorkspaceCollection).
+		UpdateOne(
+			ctx,
+			filter,
+			update,
+		)
+	if err != nil {
+		return fmt.Errorf("unable to remove member: %w", err)
+	}
+
+	return nil
+}
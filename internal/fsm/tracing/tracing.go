@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/StratuStore/fsm/internal/libs/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"io"
+	"os"
+)
+
+const serviceName = "fsm"
+
+const (
+	NoneExporter   = "none"
+	StdoutExporter = "stdout"
+	FileExporter   = "file"
+	// OTLPExporter sends spans over HTTP, it is configured by the OTEL_EXPORTER_OTLP_* variables.
+	OTLPExporter = "otlp"
+)
+
+// Propagator carries the trace context in HTTP headers and message metadata.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Provider is the tracer provider of the service, spans are exported as configured by config.Tracing.
+type Provider struct {
+	*sdktrace.TracerProvider
+	closer io.Closer
+}
+
+func New(cfg *config.Config) (*Provider, error) {
+	p := &Provider{}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.TracingExporter {
+	case NoneExporter, "":
+	case StdoutExporter:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case FileExporter:
+		var file *os.File
+		file, err = os.OpenFile(cfg.TracingFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err == nil {
+			p.closer = file
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		}
+	case OTLPExporter:
+		exporter, err = otlptracehttp.New(context.Background())
+	default:
+		err = fmt.Errorf("unknown exporter %q", cfg.TracingExporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create trace exporter: %w", err)
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	p.TracerProvider = sdktrace.NewTracerProvider(opts...)
+
+	// libraries pick the provider and the propagator up from the globals
+	otel.SetTracerProvider(p)
+	otel.SetTextMapPropagator(Propagator)
+
+	return p, nil
+}
+
+// Stop exports the remaining spans.
+func (p *Provider) Stop(ctx context.Context) error {
+	err := p.Shutdown(ctx)
+	if p.closer != nil {
+		if closeErr := p.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -38,8 +38,58 @@ type Handler struct {
 	CORSOrigins  string        `env:"HTTP_CORS_ORIGINS"`
 }
 
+// Auth configures the verification of tokens besides the HS512 ones signed with JWTSecret,
+// RS256 and ES256 tokens are verified with the keys of a JWKS from either a URL or a file.
+type Auth struct {
+	AuthJWKSURL string `env:"AUTH_JWKS_URL"`
+	// AuthJWKSFile is reloaded when it changes, so keys are rotated by replacing it
+	AuthJWKSFile    string        `env:"AUTH_JWKS_FILE"`
+	AuthJWKSRefresh time.Duration `env:"AUTH_JWKS_REFRESH" env-default:"1h"`
+	AuthIssuer      string        `env:"AUTH_ISSUER"`
+	AuthAudience    string        `env:"AUTH_AUDIENCE"`
+	// AuthRequireScopes rejects tokens without scope claims instead of granting them every scope
+	AuthRequireScopes bool `env:"AUTH_REQUIRE_SCOPES" env-default:"false"`
+}
+
 type Logger struct {
-	Level string `env:"LOGGER_LEVEL" env-default:"INFO"`
+	Level  string `env:"LOGGER_LEVEL" env-default:"INFO"`
+	Format string `env:"LOGGER_FORMAT" env-default:"text"` // text or json
+	// Redact are parts of attribute keys whose values are never logged, matched case-insensitively
+	Redact []string `env:"LOGGER_REDACT" env-default:"token,authorization,password,secret,cookie,apikey" env-separator:","`
+}
+
+type Recent struct {
+	RecentRetention  time.Duration `env:"RECENT_RETENTION" env-default:"720h"`
+	RecentBufferSize uint          `env:"RECENT_BUFFER_SIZE" env-default:"1024"`
+}
+
+type Audit struct {
+	AuditBufferSize uint `env:"AUDIT_BUFFER_SIZE" env-default:"4096"`
+}
+
+type Tracing struct {
+	TracingExporter    string  `env:"TRACING_EXPORTER" env-default:"none"` // none, stdout, file or otlp
+	TracingFile        string  `env:"TRACING_FILE" env-default:"traces.jsonl"`
+	TracingSampleRatio float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+type Health struct {
+	HealthTimeout time.Duration `env:"HEALTH_TIMEOUT" env-default:"2s"`
+	// HealthFSPing enables the round trip to an FS node, it needs FS nodes answering PingType
+	HealthFSPing bool `env:"HEALTH_FS_PING" env-default:"false"`
+	// HealthOptional are the checks whose failure only degrades the service instead of making it unready
+	HealthOptional []string `env:"HEALTH_OPTIONAL" env-default:"fs" env-separator:","`
+}
+
+type RateLimit struct {
+	RateLimitStore string `env:"RATE_LIMIT_STORE" env-default:"memory"` // memory, or mongodb to share the limits between replicas
+	// rates are the requests per second a user sustains, bursts the requests sent at once, a rate of 0 disables the limit
+	RateLimitRate        float64 `env:"RATE_LIMIT_RATE" env-default:"20"`
+	RateLimitBurst       uint    `env:"RATE_LIMIT_BURST" env-default:"60"`
+	RateLimitSearchRate  float64 `env:"RATE_LIMIT_SEARCH_RATE" env-default:"1"`
+	RateLimitSearchBurst uint    `env:"RATE_LIMIT_SEARCH_BURST" env-default:"5"`
+	RateLimitUploadRate  float64 `env:"RATE_LIMIT_UPLOAD_RATE" env-default:"2"`
+	RateLimitUploadBurst uint    `env:"RATE_LIMIT_UPLOAD_BURST" env-default:"10"`
 }
 
 type Config struct {
@@ -47,6 +97,12 @@ type Config struct {
 	MongoDB
 	Logger
 	Handler
+	Auth
+	Recent
+	Audit
+	Tracing
+	Health
+	RateLimit
 	Env string `env:"ENV" env-default:"dev"`
 }
 
@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"bytes"
 	"github.com/StratuStore/fsm/internal/libs/owncontext"
 	"github.com/StratuStore/fsm/internal/libs/ownerrors"
 	"github.com/StratuStore/fsm/internal/libs/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"io"
 	"log/slog"
 	"net/http"
 )
@@ -18,6 +20,8 @@ type Handler[T, V any] struct {
 	input                InputFunc
 	serviceWithoutResult func(owncontext.Context, *T) error
 	serviceWithResult    func(owncontext.Context, *T) (*V, error)
+	serviceWithWriter    func(owncontext.Context, *T, io.Writer) error
+	contentType          string
 }
 
 func NewWithResult[T, V any](
@@ -52,49 +56,85 @@ func NewWithoutResult[T any](
 	}
 }
 
+// NewWithWriter responds with whatever f writes instead of a JSON response, e.g. for exports.
+// The output is buffered, so errors of f are still reported as usual.
+func NewWithWriter[T any](
+	l *slog.Logger,
+	v *validator.Validate,
+	name string,
+	contentType string,
+	input InputFunc,
+	f func(owncontext.Context, *T, io.Writer) error,
+) *Handler[T, any] {
+	return &Handler[T, any]{
+		l:                 l,
+		v:                 v,
+		name:              name,
+		input:             input,
+		serviceWithWriter: f,
+		contentType:       contentType,
+	}
+}
+
+// NameLocal is the key of the name of the handler in the locals of a request, e.g. for metrics.
+const NameLocal = "handler"
+
 func (h *Handler[T, V]) Handler() func(c *fiber.Ctx) error {
+	handle := h.handleWithResult
 	if h.serviceWithoutResult != nil {
-		return h.handleWithoutResult
+		handle = h.handleWithoutResult
+	}
+	if h.serviceWithWriter != nil {
+		handle = h.handleWithWriter
 	}
 
-	return h.handleWithResult
+	return func(c *fiber.Ctx) error {
+		c.Locals(NameLocal, h.name)
+
+		return handle(c)
+	}
 }
 
 func (h *Handler[T, V]) handleWithResult(c *fiber.Ctx) error {
-	l := h.l.With(slog.String("op", h.name))
+	l := h.l.With(slog.String("op", h.name), slog.String("request_id", GetRequestID(c)))
 
 	data, err := h.processData(l, c)
 	if err != nil {
 		return err
 	}
 
-	userID, err := GetUserID(l, c)
+	identity, err := GetIdentity(l, c)
 	if err != nil {
 		return c.Status(http.StatusUnauthorized).JSON(utils.NewErrorResponse("authentification error"))
 	}
+	l = l.With(slog.String("user_id", identity.UserID))
 
-	entity, err := h.serviceWithResult(owncontext.New(c.Context(), userID), data)
+	entity, err := h.serviceWithResult(owncontext.NewWithIdentity(c.UserContext(), identity), data)
 	if err != nil {
 		return utils.ProcessError(l, c, err)
 	}
+	if tagged, ok := any(entity).(interface{ ETag() string }); ok {
+		c.Set(fiber.HeaderETag, tagged.ETag())
+	}
 
 	return c.Status(http.StatusOK).JSON(utils.NewOKResponse(entity))
 }
 
 func (h *Handler[T, V]) handleWithoutResult(c *fiber.Ctx) error {
-	l := h.l.With(slog.String("op", h.name))
+	l := h.l.With(slog.String("op", h.name), slog.String("request_id", GetRequestID(c)))
 
 	data, err := h.processData(l, c)
 	if err != nil {
 		return err
 	}
 
-	userID, err := GetUserID(l, c)
+	identity, err := GetIdentity(l, c)
 	if err != nil {
 		return c.Status(http.StatusUnauthorized).JSON(utils.NewErrorResponse("authentification error"))
 	}
+	l = l.With(slog.String("user_id", identity.UserID))
 
-	err = h.serviceWithoutResult(owncontext.New(c.Context(), userID), data)
+	err = h.serviceWithoutResult(owncontext.NewWithIdentity(c.UserContext(), identity), data)
 	if err != nil {
 		return utils.ProcessError(l, c, err)
 	}
@@ -102,6 +142,31 @@ func (h *Handler[T, V]) handleWithoutResult(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(utils.NewOKResponse[any](nil))
 }
 
+func (h *Handler[T, V]) handleWithWriter(c *fiber.Ctx) error {
+	l := h.l.With(slog.String("op", h.name), slog.String("request_id", GetRequestID(c)))
+
+	data, err := h.processData(l, c)
+	if err != nil {
+		return err
+	}
+
+	identity, err := GetIdentity(l, c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(utils.NewErrorResponse("authentification error"))
+	}
+	l = l.With(slog.String("user_id", identity.UserID))
+
+	var buf bytes.Buffer
+	err = h.serviceWithWriter(owncontext.NewWithIdentity(c.UserContext(), identity), data, &buf)
+	if err != nil {
+		return utils.ProcessError(l, c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, h.contentType)
+
+	return c.Status(http.StatusOK).Send(buf.Bytes())
+}
+
 func (h *Handler[T, V]) processData(l *slog.Logger, c *fiber.Ctx) (*T, error) {
 	var data T
 	if err := h.input(c, &data); err != nil {
@@ -120,6 +185,41 @@ func (h *Handler[T, V]) processData(l *slog.Logger, c *fiber.Ctx) (*T, error) {
 	return &data, nil
 }
 
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	TenantClaim  = "tenant"
+	RoleClaim    = "role"
+	SubtreeClaim = "subtree"
+	AdminRole    = "admin"
+)
+
+// GetIdentity reads the user, the tenant, the role and the subtree from the token, the client IP and request ID from the request,
+// the scopes from the locals set by the auth middleware.
+// Tokens without a tenant belong to the default tenant "".
+func GetIdentity(l *slog.Logger, c *fiber.Ctx) (owncontext.Identity, error) {
+	userID, err := GetUserID(l, c)
+	if err != nil {
+		return owncontext.Identity{}, err
+	}
+
+	claims := c.Locals("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	tenantID, _ := claims[TenantClaim].(string)
+	role, _ := claims[RoleClaim].(string)
+	subtree, _ := claims[SubtreeClaim].(string)
+	scopes, _ := c.Locals(ScopesLocal).([]string)
+
+	return owncontext.Identity{
+		UserID:    userID,
+		TenantID:  tenantID,
+		Admin:     role == AdminRole,
+		ClientIP:  c.IP(),
+		RequestID: GetRequestID(c),
+		Subtree:   subtree,
+		Scopes:    scopes,
+	}, nil
+}
+
 func GetUserID(l *slog.Logger, c *fiber.Ctx) (string, error) {
 	user, ok := c.Locals("user").(*jwt.Token)
 	if !ok {
@@ -127,6 +227,10 @@ func GetUserID(l *slog.Logger, c *fiber.Ctx) (string, error) {
 	}
 	claims := user.Claims.(jwt.MapClaims)
 	id, ok := claims["id"]
+	if !ok {
+		// tokens of identity providers carry the user in the standard claim
+		id, ok = claims["sub"]
+	}
 	if !ok {
 		return "", ownerrors.NewUnauthorizedError(l, "unable to get id from claims", "authentification error")
 	}
@@ -134,8 +238,10 @@ func GetUserID(l *slog.Logger, c *fiber.Ctx) (string, error) {
 	if !ok {
 		return "", ownerrors.NewUnauthorizedError(l, "unable to convert id to string", "authentification error")
 	}
+	// only the auth service signing with the shared secret marks refresh tokens with a jti,
+	// access tokens of identity providers usually have one too
 	_, ok = claims["jti"]
-	if ok {
+	if ok && user.Method == jwt.SigningMethodHS512 {
 		return "", ownerrors.NewUnauthorizedError(l, "got refreshToken instead of access", "authentification error")
 	}
 
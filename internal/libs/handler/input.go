@@ -23,6 +23,28 @@ func ParamAndQueryInput(c *fiber.Ctx, input any) error {
 	return errors.Join(c.QueryParser(input), c.ParamsParser(input))
 }
 
+func ParamAndBodyInput(c *fiber.Ctx, input any) error {
+	return errors.Join(c.BodyParser(input), c.ParamsParser(input))
+}
+
+// Headers is implemented by inputs taking request headers. Only the struct returned by Headers is parsed,
+// so other fields can not be set by headers named like them.
+type Headers interface {
+	Headers() any
+}
+
+// WithHeaders also parses the headers of the request into the fields tagged with reqHeader, e.g. If-Match.
+func WithHeaders(input InputFunc) InputFunc {
+	return func(c *fiber.Ctx, data any) error {
+		err := input(c, data)
+		if headers, ok := data.(Headers); ok {
+			err = errors.Join(err, c.ReqHeaderParser(headers.Headers()))
+		}
+
+		return err
+	}
+}
+
 func NoInput(c *fiber.Ctx, input any) error {
 	return nil
 }
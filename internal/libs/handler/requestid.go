@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"regexp"
+)
+
+// RequestIDLocal is the key of the request ID in the locals of a request, see RequestID.
+const RequestIDLocal = "requestID"
+
+// validRequestID keeps client supplied IDs from injecting anything into logs and headers.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID takes the ID of the request from the X-Request-ID header, or generates one if it is missing or malformed,
+// and echoes it in the response.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Locals(RequestIDLocal, requestID)
+		c.Set(RequestIDHeader, requestID)
+
+		return c.Next()
+	}
+}
+
+// GetRequestID returns the ID set by RequestID, or the header if the middleware did not run.
+func GetRequestID(c *fiber.Ctx) string {
+	if requestID, ok := c.Locals(RequestIDLocal).(string); ok {
+		return requestID
+	}
+
+	return c.Get(RequestIDHeader)
+}
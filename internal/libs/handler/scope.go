@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"github.com/StratuStore/fsm/internal/libs/utils"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"slices"
+)
+
+const (
+	ScopeFilesRead  = "files:read"
+	ScopeFilesWrite = "files:write"
+
+	// ScopesLocal is the key of the scopes granted to the token in the locals of a request, set by the auth middleware.
+	ScopesLocal = "scopes"
+)
+
+// AllScopes are granted to tokens without scope claims, unless scopes are required.
+var AllScopes = []string{ScopeFilesRead, ScopeFilesWrite}
+
+// RequireScope responds with 403 if the token was not granted scope.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !HasScope(c, scope) {
+			return c.Status(http.StatusForbidden).JSON(utils.NewErrorResponse("insufficient scope, " + scope + " required"))
+		}
+
+		return c.Next()
+	}
+}
+
+func HasScope(c *fiber.Ctx, scope string) bool {
+	scopes, _ := c.Locals(ScopesLocal).([]string)
+
+	return slices.Contains(scopes, scope)
+}
+
+// APITokenLocal is the key of the ID of the API token in the locals of a request authenticated with one.
+const APITokenLocal = "apiToken"
+
+// DenyAPITokens responds with 403 to requests authenticated with an API token,
+// which are meant for files and directories only.
+func DenyAPITokens() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Locals(APITokenLocal) != nil {
+			return c.Status(http.StatusForbidden).JSON(utils.NewErrorResponse("API tokens can only access files and directories"))
+		}
+
+		return c.Next()
+	}
+}
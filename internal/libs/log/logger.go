@@ -5,6 +5,14 @@ import (
 	"github.com/StratuStore/fsm/internal/libs/config"
 	"log/slog"
 	"os"
+	"strings"
+)
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+
+	redacted = "[REDACTED]"
 )
 
 func New(cfg *config.Config) (*slog.Logger, error) {
@@ -13,7 +21,42 @@ func New(cfg *config.Config) (*slog.Logger, error) {
 		return nil, fmt.Errorf("unable to parse log level: %w", err)
 	}
 
-	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: Redact(cfg.Redact),
+	}
+
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case TextFormat, "":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	case JSONFormat:
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
 
 	return slog.New(handler), nil
 }
+
+// Redact returns a slog.HandlerOptions.ReplaceAttr hiding the values of attributes
+// whose keys contain one of keys, ignoring case.
+func Redact(keys []string) func(groups []string, a slog.Attr) slog.Attr {
+	lower := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			lower = append(lower, key)
+		}
+	}
+
+	return func(_ []string, a slog.Attr) slog.Attr {
+		key := strings.ToLower(a.Key)
+		for _, sensitive := range lower {
+			if strings.Contains(key, sensitive) {
+				return slog.String(a.Key, redacted)
+			}
+		}
+
+		return a
+	}
+}
@@ -0,0 +1,21 @@
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: Redact([]string{"token", " Password "})}))
+
+	l.Info("login", slog.String("accessToken", "abc"), slog.Group("user", slog.String("password", "p")), slog.String("name", "n"))
+
+	require.NotContains(t, buf.String(), "abc")
+	require.NotContains(t, buf.String(), `"p"`)
+	require.Contains(t, buf.String(), `"accessToken":"[REDACTED]"`)
+	require.Contains(t, buf.String(), `"name":"n"`)
+}
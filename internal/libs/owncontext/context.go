@@ -2,25 +2,135 @@ package owncontext
 
 import (
 	"context"
+	"log/slog"
 )
 
 type Context interface {
 	context.Context
 	UserID() string
+	TenantID() string
+	IsAdmin() bool
+	ClientIP() string
+	RequestID() string
+	// Subtree is the ID of the directory the token of the request is limited to, empty if it is not limited.
+	Subtree() string
+	// Scopes are the scopes granted to the token of the request, see handler.ScopesLocal.
+	Scopes() []string
+	// Logger returns l with the request ID and the user ID, so the lines of a request can be correlated.
+	Logger(l *slog.Logger) *slog.Logger
+}
+
+// Identity is the caller of a request as stated by its token, together with where the request came from.
+type Identity struct {
+	UserID    string
+	TenantID  string
+	Admin     bool
+	ClientIP  string
+	RequestID string
+	Subtree   string
+	Scopes    []string
+}
+
+type (
+	tenantKey       struct{}
+	preconditionKey struct{}
+)
+
+// Precondition is the revision a document must still have when the storage changes it, see WithPrecondition.
+type Precondition struct {
+	ID       string
+	Revision uint64
+	// Applied is set by the storage once a write to the document was made conditional
+	Applied bool
 }
 
 type ctx struct {
 	context.Context
-	userID string
+	identity Identity
 }
 
 func (c *ctx) UserID() string {
-	return c.userID
+	return c.identity.UserID
+}
+
+func (c *ctx) TenantID() string {
+	return c.identity.TenantID
+}
+
+func (c *ctx) IsAdmin() bool {
+	return c.identity.Admin
+}
+
+func (c *ctx) ClientIP() string {
+	return c.identity.ClientIP
+}
+
+func (c *ctx) RequestID() string {
+	return c.identity.RequestID
+}
+
+func (c *ctx) Subtree() string {
+	return c.identity.Subtree
+}
+
+func (c *ctx) Scopes() []string {
+	return c.identity.Scopes
+}
+
+func (c *ctx) Logger(l *slog.Logger) *slog.Logger {
+	return l.With(slog.String("request_id", c.identity.RequestID), slog.String("user_id", c.identity.UserID))
 }
 
 func New(c context.Context, userID string) Context {
+	return NewWithIdentity(c, Identity{UserID: userID})
+}
+
+func NewWithIdentity(c context.Context, identity Identity) Context {
+	return &ctx{
+		Context:  context.WithValue(c, tenantKey{}, identity.TenantID),
+		identity: identity,
+	}
+}
+
+// Unscoped returns a context whose storage calls are not limited to the tenant, for calls made on behalf of other services.
+func Unscoped(c Context) Context {
+	return &ctx{
+		Context:  context.WithValue(c, tenantKey{}, nil),
+		identity: identityOf(c),
+	}
+}
+
+// WithPrecondition returns a context whose first storage write to the document id only applies while the document
+// still has revision, so a change made after checking the If-Match header of the request is not overwritten.
+func WithPrecondition(c Context, id string, revision uint64) Context {
 	return &ctx{
-		Context: c,
-		userID:  userID,
+		Context:  context.WithValue(c, preconditionKey{}, &Precondition{ID: id, Revision: revision}),
+		identity: identityOf(c),
 	}
 }
+
+func identityOf(c Context) Identity {
+	return Identity{
+		UserID:    c.UserID(),
+		TenantID:  c.TenantID(),
+		Admin:     c.IsAdmin(),
+		ClientIP:  c.ClientIP(),
+		RequestID: c.RequestID(),
+		Subtree:   c.Subtree(),
+		Scopes:    c.Scopes(),
+	}
+}
+
+// TenantID returns the tenant storage calls made with c are limited to, false if they are not limited.
+func TenantID(c context.Context) (string, bool) {
+	tenantID, ok := c.Value(tenantKey{}).(string)
+
+	return tenantID, ok
+}
+
+// PreconditionOf returns the precondition of the storage calls made with c, nil if there is none.
+func PreconditionOf(c context.Context) *Precondition {
+	precondition, _ := c.Value(preconditionKey{}).(*Precondition)
+
+	return precondition
+}
@@ -27,6 +27,16 @@ func New(err error, internalMsg string, msg string, status int) UserError {
 	}
 }
 
+// NotModifiedError answers a conditional read of a resource which still has the ETag the client knows,
+// it is sent as an empty 304 carrying the ETag.
+type NotModifiedError struct {
+	ETag string
+}
+
+func (e *NotModifiedError) Error() string {
+	return "not modified, etag " + e.ETag
+}
+
 func (e *OwnError) Unwrap() error {
 	return e.error
 }
@@ -24,6 +24,12 @@ func NewInternalError(l *slog.Logger, message string, errs ...error) error {
 	return &OwnError{error: err, internalMsg: message, msg: InternalErrorMessage, status: http.StatusInternalServerError}
 }
 
+func NewNotModifiedError(l *slog.Logger, etag string) error {
+	l.Debug("not modified", slog.String("etag", etag))
+
+	return &NotModifiedError{ETag: etag}
+}
+
 func NewValidationError(l *slog.Logger, internalMessage, userMessage string, errs ...error) error {
 	return NewError(l, http.StatusBadRequest, internalMessage, userMessage, errs...)
 }
@@ -35,3 +41,11 @@ func NewNotFoundError(l *slog.Logger, internalMessage, userMessage string, errs
 func NewUnauthorizedError(l *slog.Logger, internalMessage, userMessage string, errs ...error) error {
 	return NewError(l, http.StatusUnauthorized, internalMessage, userMessage, errs...)
 }
+
+func NewConflictError(l *slog.Logger, internalMessage, userMessage string, errs ...error) error {
+	return NewError(l, http.StatusConflict, internalMessage, userMessage, errs...)
+}
+
+func NewForbiddenError(l *slog.Logger, internalMessage, userMessage string, errs ...error) error {
+	return NewError(l, http.StatusForbidden, internalMessage, userMessage, errs...)
+}
@@ -11,6 +11,13 @@ import (
 )
 
 func ProcessError(l *slog.Logger, c *fiber.Ctx, err error) error {
+	var notModified *ownerrors.NotModifiedError
+	if errors.As(err, &notModified) {
+		c.Set(fiber.HeaderETag, notModified.ETag)
+
+		return c.SendStatus(http.StatusNotModified)
+	}
+
 	var userErr ownerrors.UserError
 	if errors.As(err, &userErr) {
 		l.Debug("unable to execute service inside handler", slog.String("err", err.Error()))